@@ -0,0 +1,62 @@
+package audit
+
+import (
+    "sync"
+    "time"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+)
+
+type Entry struct {
+    Time       time.Time         `json:"time"`
+    Login      string            `json:"login"`
+    Role       string            `json:"role"`
+    Service    string            `json:"service"`
+    Action     string            `json:"action"`
+    Id         string            `json:"id"`
+    Parameters map[string]string `json:"parameters,omitempty"`
+    Error      string            `json:"error,omitempty"`
+    /* Seq numbers the entries in the order they are recorded, from 1 */
+    Seq        uint64            `json:"-"`
+}
+
+type AuditLog struct {
+    mu         sync.Mutex
+    maxEntries int
+    entries    []Entry
+    seq        uint64
+}
+
+func NewAuditLog(maxEntries int) *AuditLog {
+    return &AuditLog{
+        maxEntries: maxEntries,
+        entries: []Entry{},
+    }
+}
+
+func (al *AuditLog) Record(entry Entry) {
+    if entry.Time.IsZero() {
+        entry.Time = time.Now()
+    }
+    log.DefaultLogger.Info("Action audit", "login", entry.Login, "role", entry.Role, 
+        "service", entry.Service, "action", entry.Action, "id", entry.Id, 
+        "parameters", entry.Parameters, "error", entry.Error)
+
+    al.mu.Lock()
+    defer al.mu.Unlock()
+    al.seq++
+    entry.Seq = al.seq
+    al.entries = append(al.entries, entry)
+    if al.maxEntries > 0 && len(al.entries) > al.maxEntries {
+        al.entries = al.entries[len(al.entries) - al.maxEntries:]
+    }
+}
+
+/* Entries returns a copy of the recorded entries, oldest first. */
+func (al *AuditLog) Entries() []Entry {
+    al.mu.Lock()
+    defer al.mu.Unlock()
+    entries := make([]Entry, len(al.entries))
+    copy(entries, al.entries)
+    return entries
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"sync"
 	"time"
 
     "github.com/samana-group/sammaws/pkg/samm"
@@ -15,89 +16,142 @@ const (
     CACHEEMPTY
 )
 
+/* Queries and the background tasks (samplers, inventory, streams) share the
+ * cache, every access goes through the lock of the item or of the map. */
 type Cache struct {
+    mu            sync.Mutex
     expires       time.Time
-    Objects       interface{}
-    NextToken     *string
+    objects       []interface{}
+    nextToken     *string
     state         CacheState
     cacheDuration time.Duration
 }
 
 func NewCache(cacheDuration time.Duration) (*Cache) {
     cacheItem := &Cache{
-        expires: time.Now().Add(-5 * time.Minute), 
+        expires: time.Now().Add(-5 * time.Minute),
+        objects: []interface{}{},
         state: CACHEEMPTY,
         cacheDuration: cacheDuration,
     }
     return cacheItem
 }
 
+/* Load returns a copy of the cached elements, the next token and whether
+ * the elements are complete and not expired, the arguments of
+ * UpdateElements. */
+func (cache *Cache) Load() ([]interface{}, *string, bool) {
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
+    valid := !cache.isExpired() && cache.state == CACHEFULL
+    return append([]interface{}{}, cache.objects...), cache.nextToken, valid
+}
+
 func (cache *Cache) Flush() {
-    cache.Objects = []interface{}{}
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
+    cache.flush()
+}
+
+func (cache *Cache) flush() {
+    cache.objects = []interface{}{}
     cache.expires = time.Now().Add(-5 * time.Minute)
-    cache.NextToken = nil
+    cache.nextToken = nil
     cache.state = CACHEEMPTY
 }
 
 func (cache *Cache) Update(se samm.SammElement, lastError error) {
-    if cache.state == CACHEFULL {
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
+    if cache.state == CACHEFULL && !cache.isExpired() {
         return
     }
-    cache.Objects = se.Elements()
+    cache.objects = se.Elements()
     cache.expires = time.Now().Add(cache.cacheDuration)
-    cache.NextToken = se.NextToken()
+    cache.nextToken = se.NextToken()
     if lastError == nil {
         cache.state = CACHEFULL
     } else {
         cache.state = CACHEPARTIAL
     }
-    log.DefaultLogger.Info("Cache refreshed.", "expires", cache.expires.String(), 
-        "elements", se.Len(), "state", cache.state, "NextToken", cache.NextToken)
+    log.DefaultLogger.Info("Cache refreshed.", "expires", cache.expires.String(),
+        "elements", se.Len(), "state", cache.state, "NextToken", cache.nextToken)
 }
 
 func (cache *Cache) IsExpired() bool {
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
+    return cache.isExpired()
+}
+
+func (cache *Cache) isExpired() bool {
     if cache.expires.Sub(time.Now()) <= 0 * time.Second {
-        cache.Flush()
+        cache.flush()
         return true
     }
     return false
 }
 
-func (cache Cache) State() CacheState {
+func (cache *Cache) State() CacheState {
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
     return cache.state
 }
 
 func (cache *Cache) IsEmpty() bool {
-    return cache.state == CACHEEMPTY
+    return cache.State() == CACHEEMPTY
 }
 
 func (cache *Cache) IsPartial() bool {
-    return (! cache.IsExpired()) && cache.state != CACHEFULL
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
+    return (! cache.isExpired()) && cache.state != CACHEFULL
 }
 
 func (cache *Cache) IsValid() bool {
-    return (! cache.IsExpired()) && (cache.state == CACHEFULL)
+    cache.mu.Lock()
+    defer cache.mu.Unlock()
+    return (! cache.isExpired()) && (cache.state == CACHEFULL)
 }
 
+/* The items expired are removed from the map at most once per cache
+ * duration, e.g. the tags of the terminated workspaces. */
 type CacheMap struct {
+    mu sync.Mutex
     cacheDuration time.Duration
     data map[string]*Cache
+    pruned time.Time
 }
 
-func NewCacheMap(cacheDuration time.Duration) CacheMap {
-    return CacheMap{
+func NewCacheMap(cacheDuration time.Duration) *CacheMap {
+    return &CacheMap{
         data: make(map[string]*Cache),
         cacheDuration: cacheDuration,
+        pruned: time.Now(),
     }
 }
 
+func (cm *CacheMap) prune() {
+    for serviceKey, cacheItem := range cm.data {
+        if cacheItem.IsExpired() {
+            delete(cm.data, serviceKey)
+        }
+    }
+    cm.pruned = time.Now()
+}
+
 func (cm *CacheMap) Get(serviceKey string) (*Cache) {
+    cm.mu.Lock()
+    if time.Since(cm.pruned) > cm.cacheDuration {
+        cm.prune()
+    }
     cacheItem, ok := cm.data[serviceKey]
     if ! ok {
         cacheItem = NewCache(cm.cacheDuration)
         cm.data[serviceKey] = cacheItem
         log.DefaultLogger.Info("Cache not initialized", "type", serviceKey)
     }
+    cm.mu.Unlock()
 
     if cacheItem.IsExpired() {
         log.DefaultLogger.Info("Cache has expired.")
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+
+	"github.com/samana-group/sammaws/pkg/samm"
+)
+
+func TestCacheLoadUpdate(t *testing.T) {
+	cm := NewCacheMap(time.Hour)
+	cacheItem := cm.Get("workspaces.Workspace")
+	if objects, nextToken, valid := cacheItem.Load(); len(objects) != 0 || nextToken != nil || valid {
+		t.Fatalf("a new cache item must be empty, got %v %v %v", objects, nextToken, valid)
+	}
+
+	sw := samm.NewSammWorkspace(nil, nil, 0)
+	sw.UpdateElements([]interface{}{&workspaces.Workspace{WorkspaceId: aws.String("ws-1")}}, nil, true)
+	cacheItem.Update(&sw, nil)
+	objects, _, valid := cm.Get("workspaces.Workspace").Load()
+	if len(objects) != 1 || !valid {
+		t.Fatalf("expected one valid element, got %v %v", objects, valid)
+	}
+
+	/* appending to the loaded elements must not change the cache */
+	objects = append(objects[:0], &workspaces.Workspace{WorkspaceId: aws.String("ws-2")})
+	objects, _, _ = cacheItem.Load()
+	if aws.StringValue(objects[0].(*workspaces.Workspace).WorkspaceId) != "ws-1" {
+		t.Fatal("Load must return a copy of the cached elements")
+	}
+
+	cacheItem.Flush()
+	if _, _, valid := cacheItem.Load(); valid || !cacheItem.IsEmpty() {
+		t.Fatal("a flushed cache item must be empty")
+	}
+}
+
+/* Run with -race: queries and background tasks use the same items. */
+func TestCacheConcurrentUse(t *testing.T) {
+	cm := NewCacheMap(time.Hour)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				cacheItem := cm.Get(fmt.Sprintf("workspaces.Tag.ws-%d", j%4))
+				objects, _, _ := cacheItem.Load()
+				sw := samm.NewSammWorkspace(nil, nil, 0)
+				objects = append(objects, &workspaces.Workspace{WorkspaceId: aws.String(fmt.Sprintf("ws-%d", i))})
+				sw.UpdateElements(objects, nil, true)
+				cacheItem.Update(&sw, nil)
+				cacheItem.IsValid()
+				if j%10 == 0 {
+					cacheItem.Flush()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestCacheMapPrune(t *testing.T) {
+	cm := NewCacheMap(10 * time.Millisecond)
+	sw := samm.NewSammWorkspace(nil, nil, 0)
+	sw.UpdateElements([]interface{}{&workspaces.Workspace{WorkspaceId: aws.String("ws-1")}}, nil, true)
+	cm.Get("workspaces.Tag.ws-1").Update(&sw, nil)
+	time.Sleep(20 * time.Millisecond)
+
+	cm.Get("workspaces.Tag.ws-2").Update(&sw, nil)
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if _, ok := cm.data["workspaces.Tag.ws-1"]; ok {
+		t.Fatal("the expired items must be removed")
+	}
+	if _, ok := cm.data["workspaces.Tag.ws-2"]; !ok {
+		t.Fatal("the new item must be kept")
+	}
+}
@@ -0,0 +1,288 @@
+package inventory
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+)
+
+/* The store keeps, for each resource, the last snapshot of the inventory
+ * and the log of the changes between successive snapshots as JSON lines.
+ * Changes older than the retention are removed by Prune. */
+type Snapshot struct {
+    Time time.Time                    `json:"time"`
+    Rows map[string]map[string]string `json:"rows"`
+}
+
+type Change struct {
+    Time     time.Time `json:"time"`
+    Resource string    `json:"resource"`
+    Key      string    `json:"key"`
+    Change   string    `json:"change"`
+    Field    string    `json:"field,omitempty"`
+    Old      string    `json:"old,omitempty"`
+    New      string    `json:"new,omitempty"`
+}
+
+type Store struct {
+    mu        sync.Mutex
+    dir       string
+    retention time.Duration
+}
+
+/* resource names are directory names, without dots or separators */
+var validResource = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func NewStore(dir string, retention time.Duration) (*Store, error) {
+    err := os.MkdirAll(dir, 0o750)
+    if err != nil {
+        return nil, err
+    }
+    return &Store{
+        dir: dir,
+        retention: retention,
+    }, nil
+}
+
+func (s *Store) resourceDir(resource string) (string, error) {
+    if !validResource.MatchString(resource) {
+        return "", fmt.Errorf("Invalid inventory resource %s.", resource)
+    }
+    return filepath.Join(s.dir, resource), nil
+}
+
+/* Save stores the snapshot of a resource and returns its changes since the
+ * previous snapshot. The first snapshot of a resource has no changes. */
+func (s *Store) Save(resource string, snapshot Snapshot) ([]Change, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    dir, err := s.resourceDir(resource)
+    if err != nil {
+        return nil, err
+    }
+    err = os.MkdirAll(dir, 0o750)
+    if err != nil {
+        return nil, err
+    }
+    previous, err := readSnapshot(filepath.Join(dir, "last.json"))
+    if err != nil {
+        return nil, err
+    }
+    changes := []Change{}
+    if previous != nil {
+        changes = Diff(resource, *previous, snapshot)
+        if len(changes) == 0 {
+            return changes, nil
+        }
+        if err = appendChanges(filepath.Join(dir, "changes.jsonl"), changes); err != nil {
+            return nil, err
+        }
+    }
+    return changes, writeSnapshot(filepath.Join(dir, "last.json"), snapshot)
+}
+
+/* Changes returns the changes of the resources between from and to, in
+ * time order. An empty list of resources means all the resources. */
+func (s *Store) Changes(resources []string, from time.Time, to time.Time) ([]Change, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if len(resources) == 0 {
+        entries, err := os.ReadDir(s.dir)
+        if err != nil {
+            return nil, err
+        }
+        for _, entry := range entries {
+            if entry.IsDir() && validResource.MatchString(entry.Name()) {
+                resources = append(resources, entry.Name())
+            }
+        }
+    }
+    out := []Change{}
+    for _, resource := range resources {
+        dir, err := s.resourceDir(resource)
+        if err != nil {
+            return nil, err
+        }
+        changes, err := readChanges(filepath.Join(dir, "changes.jsonl"))
+        if err != nil {
+            return nil, err
+        }
+        for _, change := range changes {
+            if !change.Time.Before(from) && !change.Time.After(to) {
+                out = append(out, change)
+            }
+        }
+    }
+    sort.SliceStable(out, func(a, b int) bool { return out[a].Time.Before(out[b].Time) })
+    return out, nil
+}
+
+/* Prune removes the changes older than the retention. The last snapshot is
+ * always kept. */
+func (s *Store) Prune(now time.Time) error {
+    if s.retention <= 0 {
+        return nil
+    }
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    limit := now.Add(-s.retention)
+    entries, err := os.ReadDir(s.dir)
+    if err != nil {
+        return err
+    }
+    for _, entry := range entries {
+        if !entry.IsDir() {
+            continue
+        }
+        path := filepath.Join(s.dir, entry.Name(), "changes.jsonl")
+        changes, err := readChanges(path)
+        if err != nil {
+            return err
+        }
+        kept := []Change{}
+        for _, change := range changes {
+            if !change.Time.Before(limit) {
+                kept = append(kept, change)
+            }
+        }
+        if len(kept) == len(changes) {
+            continue
+        }
+        tmp := path + ".tmp"
+        os.Remove(tmp)
+        if err = appendChanges(tmp, kept); err != nil {
+            return err
+        }
+        if err = os.Rename(tmp, path); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+/* Diff returns the rows added and removed, and a change for each modified
+ * field of the other rows. */
+func Diff(resource string, previous Snapshot, current Snapshot) []Change {
+    changes := []Change{}
+    keys := make([]string, 0, len(current.Rows))
+    for key := range current.Rows {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    for _, key := range keys {
+        row := current.Rows[key]
+        old, ok := previous.Rows[key]
+        if !ok {
+            changes = append(changes, Change{Time: current.Time, Resource: resource, Key: key, Change: "added"})
+            continue
+        }
+        fields := make([]string, 0, len(row))
+        for field := range row {
+            fields = append(fields, field)
+        }
+        for field := range old {
+            if _, ok := row[field]; !ok {
+                fields = append(fields, field)
+            }
+        }
+        sort.Strings(fields)
+        for _, field := range fields {
+            if row[field] != old[field] {
+                changes = append(changes, Change{
+                    Time: current.Time,
+                    Resource: resource,
+                    Key: key,
+                    Change: "modified",
+                    Field: field,
+                    Old: old[field],
+                    New: row[field],
+                })
+            }
+        }
+    }
+    keys = keys[:0]
+    for key := range previous.Rows {
+        if _, ok := current.Rows[key]; !ok {
+            keys = append(keys, key)
+        }
+    }
+    sort.Strings(keys)
+    for _, key := range keys {
+        changes = append(changes, Change{Time: current.Time, Resource: resource, Key: key, Change: "removed"})
+    }
+    return changes
+}
+
+func readSnapshot(path string) (*Snapshot, error) {
+    content, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    var snapshot Snapshot
+    if err = json.Unmarshal(content, &snapshot); err != nil {
+        log.DefaultLogger.Warn("Invalid snapshot ignored", "path", path, "error", err.Error())
+        return nil, nil
+    }
+    return &snapshot, nil
+}
+
+func writeSnapshot(path string, snapshot Snapshot) error {
+    content, err := json.Marshal(snapshot)
+    if err != nil {
+        return err
+    }
+    tmp := path + ".tmp"
+    if err = os.WriteFile(tmp, content, 0o640); err != nil {
+        return err
+    }
+    return os.Rename(tmp, path)
+}
+
+func appendChanges(path string, changes []Change) error {
+    f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+    if err != nil {
+        return err
+    }
+    encoder := json.NewEncoder(f)
+    for _, change := range changes {
+        if err = encoder.Encode(change); err != nil {
+            f.Close()
+            return err
+        }
+    }
+    return f.Close()
+}
+
+func readChanges(path string) ([]Change, error) {
+    f, err := os.Open(path)
+    if os.IsNotExist(err) {
+        return []Change{}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    changes := []Change{}
+    scanner := bufio.NewScanner(f)
+    scanner.Buffer(make([]byte, 64 * 1024), 1024 * 1024)
+    for scanner.Scan() {
+        var change Change
+        if err := json.Unmarshal(scanner.Bytes(), &change); err != nil {
+            log.DefaultLogger.Warn("Invalid change", "path", path, "error", err.Error())
+            continue
+        }
+        changes = append(changes, change)
+    }
+    return changes, scanner.Err()
+}
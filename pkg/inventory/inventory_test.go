@@ -0,0 +1,132 @@
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	previous := Snapshot{Rows: map[string]map[string]string{
+		"ws-1": {"State": "AVAILABLE", "UserName": "alice"},
+		"ws-2": {"State": "AVAILABLE"},
+		"ws-3": {"State": "STOPPED"},
+	}}
+	current := Snapshot{Time: now, Rows: map[string]map[string]string{
+		"ws-1": {"State": "STOPPED", "ComputerName": "A1"},
+		"ws-3": {"State": "STOPPED"},
+		"ws-4": {"State": "PENDING"},
+	}}
+	want := []Change{
+		{Time: now, Resource: "DescribeWorkspaces", Key: "ws-1", Change: "modified", Field: "ComputerName", New: "A1"},
+		{Time: now, Resource: "DescribeWorkspaces", Key: "ws-1", Change: "modified", Field: "State", Old: "AVAILABLE", New: "STOPPED"},
+		{Time: now, Resource: "DescribeWorkspaces", Key: "ws-1", Change: "modified", Field: "UserName", Old: "alice"},
+		{Time: now, Resource: "DescribeWorkspaces", Key: "ws-4", Change: "added"},
+		{Time: now, Resource: "DescribeWorkspaces", Key: "ws-2", Change: "removed"},
+	}
+	if got := Diff("DescribeWorkspaces", previous, current); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected changes\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestSaveChanges(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	snapshots := []Snapshot{
+		{Time: start, Rows: map[string]map[string]string{"ws-1": {"State": "AVAILABLE"}}},
+		{Time: start.Add(time.Hour), Rows: map[string]map[string]string{"ws-1": {"State": "STOPPED"}}},
+		{Time: start.Add(2 * time.Hour), Rows: map[string]map[string]string{"ws-1": {"State": "STOPPED"}}},
+	}
+	for i, snapshot := range snapshots {
+		changes, err := s.Save("DescribeWorkspaces", snapshot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != []int{0, 1, 0}[i] {
+			t.Fatalf("snapshot %d: unexpected changes %+v", i, changes)
+		}
+	}
+	if _, err = s.Save("DescribeFleets", Snapshot{Time: start.Add(3 * time.Hour), Rows: map[string]map[string]string{}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Save("DescribeFleets", Snapshot{Time: start.Add(4 * time.Hour), Rows: map[string]map[string]string{"f-1": {}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := os.ReadDir(filepath.Join(dir, "DescribeWorkspaces"))
+	names := []string{}
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"changes.jsonl", "last.json"}) {
+		t.Fatalf("only the last snapshot and the changes must be kept, got %v", names)
+	}
+
+	changes, err := s.Changes(nil, start, start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 2 || changes[0].Key != "ws-1" || changes[1].Key != "f-1" {
+		t.Fatalf("expected the changes of all the resources in time order, got %+v", changes)
+	}
+	changes, err = s.Changes([]string{"DescribeFleets"}, start, start.Add(90*time.Minute))
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected no change of the fleets in the range, got %+v, %v", changes, err)
+	}
+}
+
+func TestInvalidResource(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, resource := range []string{"", ".", "..", "../secrets", "a/b", "Describe.Fleets"} {
+		if _, err := s.Save(resource, Snapshot{}); err == nil {
+			t.Errorf("%q: Save must reject the resource", resource)
+		}
+		if _, err := s.Changes([]string{resource}, time.Time{}, time.Now()); err == nil {
+			t.Errorf("%q: Changes must reject the resource", resource)
+		}
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	states := []string{"AVAILABLE", "STOPPED", "AVAILABLE"}
+	for i, state := range states {
+		snapshot := Snapshot{
+			Time: now.Add(time.Duration(i-2) * 36 * time.Hour),
+			Rows: map[string]map[string]string{"ws-1": {"State": state}},
+		}
+		if _, err = s.Save("DescribeWorkspaces", snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = s.Prune(now); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := s.Changes(nil, now.Add(-30*24*time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].New != "AVAILABLE" {
+		t.Fatalf("expected the change of the last day only, got %+v", changes)
+	}
+
+	/* the last snapshot is kept: an unchanged snapshot gives no change */
+	unchanged, err := s.Save("DescribeWorkspaces", Snapshot{Time: now, Rows: map[string]map[string]string{"ws-1": {"State": "AVAILABLE"}}})
+	if err != nil || len(unchanged) != 0 {
+		t.Fatalf("expected no change, got %+v, %v", unchanged, err)
+	}
+}
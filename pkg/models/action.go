@@ -1,7 +1,9 @@
 package models
 
 type ActionModel struct {
-    Service string `json:"service"`
-    Action  string `json:"action"`
-    Id      string `json:"id"`
+    Service    string            `json:"service"`
+    Action     string            `json:"action"`
+    Id         string            `json:"id"`
+    Resource   string            `json:"resource,omitempty"`
+    Parameters map[string]string `json:"parameters,omitempty"`
 }
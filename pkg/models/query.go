@@ -8,11 +8,35 @@ import (
     "strconv"
 )
 
+/* Operator is one of =, !=, in, not in, contains, regex, <, >, <=, >=,
+ * is empty and is not empty. An empty operator means =. */
 type FilterCondition struct {
     Property      string `json:"outProperty"`
+    Operator      string `json:"operator,omitempty"`
     Value         string `json:"value"`
 }
 
+/* IsEqual returns true when the condition can be sent to AWS as is. */
+func (f FilterCondition) IsEqual() bool {
+    return f.Operator == "" || f.Operator == "="
+}
+
+/* SortKey orders the rows of a frame on a property, ascending unless Desc. */
+type SortKey struct {
+    Property      string `json:"property"`
+    Desc          bool   `json:"desc,omitempty"`
+}
+
+/* Aggregation is one of count, count_distinct, min, max, sum and avg.
+ * Count may have no property, the other functions need one. */
+type Aggregation struct {
+    Function      string `json:"function"`
+    Property      string `json:"property,omitempty"`
+    Alias         string `json:"alias,omitempty"`
+}
+
+/* Limit caps the number of elements collected from AWS, while Offset and
+ * RowLimit select the rows sent in the frame after filtering and sorting. */
 type QueryModel struct{
     Service       string `json:"service"`
     ServiceQuery  string `json:"service_query"`
@@ -21,6 +45,43 @@ type QueryModel struct{
     Limit         int    `json:"Limit,omitempty"`
     FieldList     []string `json:"fieldList,omitempty"`
     FilterConditions []FilterCondition `json:"filterConditions,omitempty"`
+    SortBy        []SortKey `json:"sortBy,omitempty"`
+    Offset        int    `json:"offset,omitempty"`
+    RowLimit      int    `json:"rowLimit,omitempty"`
+    GroupBy       []string `json:"groupBy,omitempty"`
+    Aggregations  []Aggregation `json:"aggregations,omitempty"`
+    Format        string `json:"format,omitempty"`
+    MetricName    string `json:"metricName,omitempty"`
+    Statistic     string `json:"statistic,omitempty"`
+    Dimension     string `json:"dimension,omitempty"`
+    Search        string `json:"search,omitempty"`
+    NextToken     string `json:"nextToken,omitempty"`
+    IdleDays      int    `json:"idleDays,omitempty"`
+    RarelyUsedDays int   `json:"rarelyUsedDays,omitempty"`
+}
+
+func (q QueryModel) IsAggregation() bool {
+    return len(q.GroupBy) > 0 || len(q.Aggregations) > 0 || q.IsNumeric()
+}
+
+/* IsNumeric returns true when the query result is a numeric frame usable
+ * by server side expressions and alerting instead of a table. */
+func (q QueryModel) IsNumeric() bool {
+    return q.Format == "numeric"
+}
+
+/* ReferencedFields returns the fields the query reads: the field list,
+ * the sort keys, the group by fields and the aggregated properties. */
+func (q QueryModel) ReferencedFields() []string {
+    fields := append([]string{}, q.FieldList...)
+    for _, sortKey := range q.SortBy {
+        fields = append(fields, sortKey.Property)
+    }
+    fields = append(fields, q.GroupBy...)
+    for _, aggregation := range q.Aggregations {
+        fields = append(fields, aggregation.Property)
+    }
+    return fields
 }
 
 func NewQueryModelFromJSON(jsondata []byte) (QueryModel, error) {
@@ -49,11 +110,18 @@ func NewQueryModelFromUrl(urlstr string) (QueryModel, error) {
         for _, filterCondition := range filterConditions {
             before, after, found := strings.Cut(filterCondition, ":")
             if found {
-                q.FilterConditions = append(q.FilterConditions, FilterCondition{before, after})
+                q.FilterConditions = append(q.FilterConditions, FilterCondition{Property: before, Value: after})
             }
         }
     }
 
+    for _, sortKey := range params["sortBy"] {
+        property, order, _ := strings.Cut(sortKey, ":")
+        q.SortBy = append(q.SortBy, SortKey{Property: property, Desc: strings.EqualFold(order, "desc")})
+    }
+    q.Offset, _   = strconv.Atoi(params.Get("offset"))
+    q.RowLimit, _ = strconv.Atoi(params.Get("rowLimit"))
+
     if q.Service == "" {
         return QueryModel{}, fmt.Errorf("Parameter 'service' is mandatory")
     }
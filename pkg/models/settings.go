@@ -16,9 +16,26 @@ type PluginSettings struct {
 	MaxRetryDelay int               `json:"maxRetryDelay,omitempty"`
 	MaxThrottleDelay int            `json:"maxThrottleDelay,omitempty"`
 	CacheSeconds int                `json:"cacheSeconds,omitemtpy"`
+	DataPath  string                `json:"dataPath,omitempty"`
+	SampleRetentionDays int         `json:"sampleRetentionDays,omitempty"`
+	Samplers  []SamplerSettings     `json:"samplers,omitempty"`
+	StreamIntervalSeconds int       `json:"streamIntervalSeconds,omitempty"`
+	/* seconds between the inventory snapshots, unset or 0 disables them */
+	InventoryIntervalSeconds int    `json:"inventoryIntervalSeconds,omitempty"`
+	/* JSON or CSV pricing table, see the pricing package */
+	PricingFile string              `json:"pricingFile,omitempty"`
+	AutoStopHoursPerMonth float64   `json:"autoStopHoursPerMonth,omitempty"`
 	Secrets   *SecretPluginSettings `json:"-"`
 }
 
+/* A sampler runs an aggregate query every IntervalSeconds and stores the
+ * values, so that they can be queried later as time series. */
+type SamplerSettings struct {
+	Name            string     `json:"name"`
+	IntervalSeconds int        `json:"intervalSeconds"`
+	Query           QueryModel `json:"query"`
+}
+
 type SecretPluginSettings struct {
 	AccessSecret string `json:"accessSecret,omitempty"`
 	AccessToken string `json:"accessToken,omitempty"`
@@ -0,0 +1,249 @@
+package plugin
+
+import (
+    "fmt"
+    "strings"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+
+    "github.com/samana-group/sammaws/pkg/models"
+    "github.com/samana-group/sammaws/pkg/samm"
+)
+
+/* Aggregation mode: the filtered elements are grouped on the group by
+ * properties and each group gives one row with the group values as
+ * strings followed by one column per aggregation. */
+type aggregator struct {
+    models.Aggregation
+    Name  string
+    field *data.Field
+}
+
+type aggregateGroup struct {
+    keys []string
+    rows []int
+}
+
+func newAggregator(elements samm.SammElement, aggregation models.Aggregation) (aggregator, error) {
+    a := aggregator{Aggregation: aggregation}
+    a.Function = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(a.Function)), " ", "_")
+    switch a.Function {
+    case "count", "count_distinct", "min", "max", "sum", "avg":
+    default:
+        return a, fmt.Errorf("Invalid aggregation function '%s'.", aggregation.Function)
+    }
+    a.Name = a.Alias
+    if a.Name == "" {
+        a.Name = a.Function
+        if a.Property != "" {
+            a.Name = a.Function + "(" + a.Property + ")"
+        }
+    }
+    if a.Property == "" {
+        if a.Function != "count" {
+            return a, fmt.Errorf("Aggregation %s needs a property.", a.Function)
+        }
+        return a, nil
+    }
+    fieldType, ok := elements.AttributeType(a.Property)
+    if !ok {
+        return a, fmt.Errorf("Invalid property in aggregation %s.", a.Property)
+    }
+    a.field = data.NewField(a.Property, nil, fieldType)
+    switch a.Function {
+    case "min", "max", "sum", "avg":
+        if !a.field.Type().Numeric() {
+            return a, fmt.Errorf("Aggregation %s needs a numeric property, %s is not.", a.Function, a.Property)
+        }
+    }
+    for i := 0; i < elements.Len(); i++ {
+        elements.AppendData(i, a.field, a.Property)
+    }
+    return a, nil
+}
+
+func (a aggregator) newField(groups int) *data.Field {
+    if a.Function == "count" || a.Function == "count_distinct" {
+        return data.NewField(a.Name, nil, make([]int64, groups))
+    }
+    return data.NewField(a.Name, nil, make([]*float64, groups))
+}
+
+func (a aggregator) compute(rows []int) interface{} {
+    if a.field == nil {
+        return int64(len(rows))
+    }
+    switch a.Function {
+    case "count":
+        count := int64(0)
+        for _, row := range rows {
+            if samm.FieldValue(a.field, row) != nil {
+                count++
+            }
+        }
+        return count
+    case "count_distinct":
+        distinct := map[string]bool{}
+        for _, row := range rows {
+            if value := samm.FieldValue(a.field, row); value != nil {
+                distinct[fmt.Sprint(value)] = true
+            }
+        }
+        return int64(len(distinct))
+    }
+    var result *float64
+    count := 0
+    for _, row := range rows {
+        value, ok := samm.FieldValue(a.field, row).(float64)
+        if !ok {
+            continue
+        }
+        count++
+        if result == nil {
+            result = &value
+            continue
+        }
+        switch a.Function {
+        case "min":
+            if value < *result {
+                *result = value
+            }
+        case "max":
+            if value > *result {
+                *result = value
+            }
+        case "sum", "avg":
+            *result += value
+        }
+    }
+    if a.Function == "avg" && result != nil {
+        *result = *result / float64(count)
+    }
+    return result
+}
+
+func aggregateFrame(elements samm.SammElement, rows []int, queryData models.QueryModel, Name string) (*data.Frame, error) {
+    groupFields := make([]*data.Field, len(queryData.GroupBy))
+    for g, property := range queryData.GroupBy {
+        fieldType, ok := elements.AttributeType(property)
+        if !ok {
+            return nil, fmt.Errorf("Invalid property in group by %s.", property)
+        }
+        groupFields[g] = data.NewField(property, nil, fieldType)
+        for i := 0; i < elements.Len(); i++ {
+            elements.AppendData(i, groupFields[g], property)
+        }
+    }
+    aggregations := queryData.Aggregations
+    if len(aggregations) == 0 {
+        aggregations = []models.Aggregation{{Function: "count"}}
+    }
+    aggregators := make([]aggregator, len(aggregations))
+    for i, aggregation := range aggregations {
+        a, err := newAggregator(elements, aggregation)
+        if err != nil {
+            return nil, err
+        }
+        aggregators[i] = a
+    }
+
+    /* Groups are kept in the order they first appear */
+    groups := []*aggregateGroup{}
+    groupIndex := map[string]*aggregateGroup{}
+    for _, row := range rows {
+        keys := make([]string, len(groupFields))
+        for g, field := range groupFields {
+            if value := samm.FieldValue(field, row); value != nil {
+                keys[g] = samm.ValueString(value)
+            }
+        }
+        key := strings.Join(keys, "\x00")
+        group, ok := groupIndex[key]
+        if !ok {
+            group = &aggregateGroup{keys: keys}
+            groupIndex[key] = group
+            groups = append(groups, group)
+        }
+        group.rows = append(group.rows, row)
+    }
+    if len(groupFields) == 0 && len(groups) == 0 {
+        groups = append(groups, &aggregateGroup{})
+    }
+
+    frame := data.NewFrame(Name)
+    frame.Meta = &data.FrameMeta{
+        PreferredVisualization: "table",
+    }
+    for _, property := range queryData.GroupBy {
+        frame.Fields = append(frame.Fields, data.NewField(property, nil, make([]string, len(groups))))
+    }
+    for _, a := range aggregators {
+        frame.Fields = append(frame.Fields, a.newField(len(groups)))
+    }
+    for i, group := range groups {
+        for g := range groupFields {
+            frame.Fields[g].Set(i, group.keys[g])
+        }
+        for j, a := range aggregators {
+            frame.Fields[len(groupFields) + j].Set(i, a.compute(group.rows))
+        }
+    }
+
+    /* Sort keys and paging apply to the rows of the aggregated frame */
+    order := make([]int, len(groups))
+    for i := range order {
+        order[i] = i
+    }
+    sortFields := make([]*data.Field, len(queryData.SortBy))
+    for k, sortKey := range queryData.SortBy {
+        field, index := frame.FieldByName(sortKey.Property)
+        if index < 0 {
+            return nil, fmt.Errorf("Invalid property in sort %s.", sortKey.Property)
+        }
+        sortFields[k] = field
+    }
+    samm.SortRows(order, sortFields, queryData.SortBy)
+    order = samm.PageRows(order, queryData.Offset, queryData.RowLimit)
+    frame = reorderFrame(frame, order)
+    if queryData.IsNumeric() {
+        return numericWideFrame(frame, len(groupFields), Name), nil
+    }
+    return frame, nil
+}
+
+func reorderFrame(frame *data.Frame, order []int) *data.Frame {
+    out := frame.EmptyCopy()
+    for _, row := range order {
+        out.AppendRow(frame.RowCopy(row)...)
+    }
+    return out
+}
+
+/* numericWideFrame converts an aggregated frame to the numeric wide format
+ * of the data plane contract: a single row with one number field per group
+ * and aggregation, the group values being the labels of the field. */
+func numericWideFrame(aggregated *data.Frame, groupFields int, Name string) *data.Frame {
+    frame := data.NewFrame(Name)
+    frame.Meta = &data.FrameMeta{
+        Type: data.FrameTypeNumericWide,
+        TypeVersion: data.FrameTypeVersion{0, 1},
+    }
+    rows, _ := aggregated.RowLen()
+    for _, valueField := range aggregated.Fields[groupFields:] {
+        for row := 0; row < rows; row++ {
+            var labels data.Labels
+            if groupFields > 0 {
+                labels = data.Labels{}
+                for _, groupField := range aggregated.Fields[:groupFields] {
+                    labels[groupField.Name] = groupField.At(row).(string)
+                }
+            }
+            value, err := valueField.NullableFloatAt(row)
+            if err != nil {
+                value = nil
+            }
+            frame.Fields = append(frame.Fields, data.NewField(valueField.Name, labels, []*float64{value}))
+        }
+    }
+    return frame
+}
@@ -0,0 +1,171 @@
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/samana-group/sammaws/pkg/models"
+	"github.com/samana-group/sammaws/pkg/samm"
+)
+
+type testWorkspace struct {
+	state    string
+	bundle   string
+	rootSize *int64
+}
+
+func aggregateWorkspaces(items ...testWorkspace) samm.SammWorkspace {
+	sw := samm.NewSammWorkspace(nil, nil, 0)
+	elements := []interface{}{}
+	for i, item := range items {
+		elements = append(elements, &workspaces.Workspace{
+			WorkspaceId:         aws.String(fmt.Sprintf("ws-%d", i)),
+			State:               aws.String(item.state),
+			BundleId:            aws.String(item.bundle),
+			WorkspaceProperties: &workspaces.WorkspaceProperties{RootVolumeSizeGib: item.rootSize},
+		})
+	}
+	sw.UpdateElements(elements, nil, true)
+	return sw
+}
+
+func frameColumn(t *testing.T, frame *data.Frame, name string) []interface{} {
+	t.Helper()
+	field, _ := frame.FieldByName(name)
+	if field == nil {
+		t.Fatalf("missing field %s", name)
+	}
+	values := []interface{}{}
+	for i := 0; i < field.Len(); i++ {
+		value, ok := field.ConcreteAt(i)
+		if !ok {
+			value = nil
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestAggregateCountDistinct(t *testing.T) {
+	sw := aggregateWorkspaces(
+		testWorkspace{state: "AVAILABLE", bundle: "b-1"},
+		testWorkspace{state: "AVAILABLE", bundle: "b-2"},
+		testWorkspace{state: "AVAILABLE", bundle: "b-1"},
+		testWorkspace{state: "STOPPED", bundle: "b-3"},
+	)
+	queryData := models.QueryModel{
+		GroupBy:      []string{"State"},
+		Aggregations: []models.Aggregation{{Function: "count distinct", Property: "BundleId", Alias: "Bundles"}},
+	}
+	frame, err := CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := frameColumn(t, frame, "Bundles"); !reflect.DeepEqual(got, []interface{}{int64(2), int64(1)}) {
+		t.Fatalf("unexpected distinct counts %v", got)
+	}
+}
+
+func TestAggregateIgnoresNilValues(t *testing.T) {
+	sw := aggregateWorkspaces(
+		testWorkspace{state: "AVAILABLE", rootSize: aws.Int64(80)},
+		testWorkspace{state: "AVAILABLE"},
+		testWorkspace{state: "AVAILABLE", rootSize: aws.Int64(100)},
+		testWorkspace{state: "STOPPED"},
+	)
+	queryData := models.QueryModel{
+		GroupBy: []string{"State"},
+		Aggregations: []models.Aggregation{
+			{Function: "avg", Property: "WorkspaceProperties.RootVolumeSizeGib", Alias: "Avg"},
+			{Function: "count", Property: "WorkspaceProperties.RootVolumeSizeGib", Alias: "Sized"},
+			{Function: "count", Alias: "Workspaces"},
+		},
+	}
+	frame, err := CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := frameColumn(t, frame, "Avg"); !reflect.DeepEqual(got, []interface{}{90.0, nil}) {
+		t.Fatalf("avg must skip the missing values, got %v", got)
+	}
+	if got := frameColumn(t, frame, "Sized"); !reflect.DeepEqual(got, []interface{}{int64(2), int64(0)}) {
+		t.Fatalf("count of a property must skip the missing values, got %v", got)
+	}
+	if got := frameColumn(t, frame, "Workspaces"); !reflect.DeepEqual(got, []interface{}{int64(3), int64(1)}) {
+		t.Fatalf("unexpected row counts %v", got)
+	}
+}
+
+func TestAggregateGroupOrder(t *testing.T) {
+	sw := aggregateWorkspaces(
+		testWorkspace{state: "STOPPED"},
+		testWorkspace{state: "AVAILABLE"},
+		testWorkspace{state: "UNHEALTHY"},
+		testWorkspace{state: "AVAILABLE"},
+		testWorkspace{state: "AVAILABLE"},
+		testWorkspace{state: "UNHEALTHY"},
+	)
+	queryData := models.QueryModel{GroupBy: []string{"State"}}
+	frame, err := CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := frameColumn(t, frame, "State"); !reflect.DeepEqual(got, []interface{}{"STOPPED", "AVAILABLE", "UNHEALTHY"}) {
+		t.Fatalf("groups must keep the order they first appear in, got %v", got)
+	}
+
+	queryData.SortBy = []models.SortKey{{Property: "count", Desc: true}, {Property: "State"}}
+	queryData.RowLimit = 2
+	frame, err = CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := frameColumn(t, frame, "State"); !reflect.DeepEqual(got, []interface{}{"AVAILABLE", "UNHEALTHY"}) {
+		t.Fatalf("unexpected sorted groups %v", got)
+	}
+
+	queryData.SortBy = []models.SortKey{{Property: "InstanceId"}}
+	if _, err = CreateFrame(sw, queryData, "A"); err == nil {
+		t.Fatal("sorting on a field of the elements that is not in the aggregated frame must fail")
+	}
+}
+
+func TestAggregateEmptyGroup(t *testing.T) {
+	sw := aggregateWorkspaces()
+	frame, err := CreateFrame(sw, models.QueryModel{Aggregations: []models.Aggregation{{Function: "count"}}}, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := frameColumn(t, frame, "count"); !reflect.DeepEqual(got, []interface{}{int64(0)}) {
+		t.Fatalf("without group by, no element must give a single zero count, got %v", got)
+	}
+
+	frame, err = CreateFrame(sw, models.QueryModel{GroupBy: []string{"State"}}, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := frame.RowLen(); rows != 0 {
+		t.Fatalf("with group by, no element must give no group, got %d rows", rows)
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	sw := aggregateWorkspaces(testWorkspace{state: "AVAILABLE"})
+	tests := []models.QueryModel{
+		{Aggregations: []models.Aggregation{{Function: "median", Property: "WorkspaceProperties.RootVolumeSizeGib"}}},
+		{Aggregations: []models.Aggregation{{Function: "sum"}}},
+		{Aggregations: []models.Aggregation{{Function: "sum", Property: "State"}}},
+		{Aggregations: []models.Aggregation{{Function: "max", Property: "Owner"}}},
+		{GroupBy: []string{"Owner"}},
+	}
+	for _, queryData := range tests {
+		if _, err := CreateFrame(sw, queryData, "A"); err == nil {
+			t.Errorf("%+v: expected an error", queryData)
+		}
+	}
+}
@@ -3,8 +3,12 @@ package plugin
 import (
     "fmt"
     "errors"
+    "strconv"
+    "strings"
+    "time"
     "encoding/json"
 
+    "github.com/aws/aws-sdk-go/aws"
     "github.com/aws/aws-sdk-go/service/appstream"
 
     "github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -67,6 +71,51 @@ func (a AppstreamQuery) QueryData() backend.DataResponse {
     case "ListAssociatedFleetsFields":
         return a.associatedFleetsFieldsToResponse()
 
+    case "DescribeUsers":
+        return a.usersToResponse()
+    case "DescribeUsersFields":
+        return a.usersFieldsToResponse()
+
+    case "DescribeUserStackAssociations":
+        return a.userStackAssociationsToResponse()
+    case "DescribeUserStackAssociationsFields":
+        return a.userStackAssociationsFieldsToResponse()
+
+    case "DescribeImages":
+        return a.imagesToResponse()
+    case "DescribeImagesFields":
+        return a.imagesFieldsToResponse()
+
+    case "DescribeImageBuilders":
+        return a.imageBuildersToResponse()
+    case "DescribeImageBuildersFields":
+        return a.imageBuildersFieldsToResponse()
+
+    case "DescribeApplications":
+        return a.applicationsToResponse()
+    case "DescribeApplicationsFields":
+        return a.applicationsFieldsToResponse()
+
+    case "DescribeAppBlocks":
+        return a.appBlocksToResponse()
+    case "DescribeAppBlocksFields":
+        return a.appBlocksFieldsToResponse()
+
+    case "DescribeApplicationFleetAssociations":
+        return a.applicationFleetAssociationsToResponse()
+    case "DescribeApplicationFleetAssociationsFields":
+        return a.applicationFleetAssociationsFieldsToResponse()
+
+    case "DescribeEntitlements":
+        return a.entitlementsToResponse()
+    case "DescribeEntitlementsFields":
+        return a.entitlementsFieldsToResponse()
+
+    case "ListEntitledApplications":
+        return a.entitledApplicationsToResponse()
+    case "ListEntitledApplicationsFields":
+        return a.entitledApplicationsFieldsToResponse()
+
     }
     return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("Not Implemented service_query %v", a.queryData.ServiceQuery))
 }
@@ -84,6 +133,54 @@ func (a AppstreamQuery) CallAction() ([]byte, error) {
     switch a.actionData.Action {
     case "expire-session":
         return a.expireSession()
+
+    case "start-fleet":
+        return a.startFleet()
+
+    case "stop-fleet":
+        return a.stopFleet()
+
+    case "update-fleet":
+        return a.updateFleet()
+
+    case "associate-fleet":
+        return a.associateFleet()
+
+    case "disassociate-fleet":
+        return a.disassociateFleet()
+
+    case "create-streaming-url":
+        return a.createStreamingURL()
+
+    case "create-user":
+        return a.createUser()
+
+    case "enable-user":
+        return a.enableUser()
+
+    case "disable-user":
+        return a.disableUser()
+
+    case "delete-user":
+        return a.deleteUser()
+
+    case "associate-user-stack":
+        return a.associateUserStack()
+
+    case "disassociate-user-stack":
+        return a.disassociateUserStack()
+
+    case "start-image-builder":
+        return a.startImageBuilder()
+
+    case "stop-image-builder":
+        return a.stopImageBuilder()
+
+    case "delete-image-builder":
+        return a.deleteImageBuilder()
+
+    case "create-image-builder-streaming-url":
+        return a.createImageBuilderStreamingURL()
     
     case "echo":
         return a.echo()
@@ -104,6 +201,102 @@ func (a AppstreamQuery) ListActions() ([]byte, error) {
             Disabled: disabled,
             Confirm: true,
         },
+        {
+            Action: "start-fleet",
+            DisplayName: "Start Fleet",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "stop-fleet",
+            DisplayName: "Stop Fleet",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "update-fleet",
+            DisplayName: "Update Fleet",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "associate-fleet",
+            DisplayName: "Associate Fleet",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "disassociate-fleet",
+            DisplayName: "Disassociate Fleet",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "create-streaming-url",
+            DisplayName: "Create Streaming URL",
+            Disabled: disabled,
+            Confirm: false,
+        },
+        {
+            Action: "create-user",
+            DisplayName: "Create User",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "enable-user",
+            DisplayName: "Enable User",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "disable-user",
+            DisplayName: "Disable User",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "delete-user",
+            DisplayName: "Delete User",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "associate-user-stack",
+            DisplayName: "Assign User to Stack",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "disassociate-user-stack",
+            DisplayName: "Unassign User from Stack",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "start-image-builder",
+            DisplayName: "Start Image Builder",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "stop-image-builder",
+            DisplayName: "Stop Image Builder",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "delete-image-builder",
+            DisplayName: "Delete Image Builder",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "create-image-builder-streaming-url",
+            DisplayName: "Connect to Image Builder",
+            Disabled: disabled,
+            Confirm: false,
+        },
         {
             Action: "echo",
             DisplayName: "Echo" + a.role,
@@ -153,6 +346,7 @@ func (w AppstreamQuery) fleetFieldsToResponse() backend.DataResponse {
         "UsbDeviceFilterStrings",
         "VpcConfig",
     }
+    fields = append(fields, samm.NewSammFleet(w.svc, nil, 0).NestedFieldList()...)
     return fieldsToResponse(fields, fieldlist)
 }
 
@@ -169,7 +363,7 @@ func (a AppstreamQuery) fleetsToResponse() backend.DataResponse {
         }
     } else {
         cacheItem := a.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -177,7 +371,7 @@ func (a AppstreamQuery) fleetsToResponse() backend.DataResponse {
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, a.queryData.FieldList, a.refID)
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
     if err != nil {
         response.Error = err
         return response
@@ -207,6 +401,7 @@ func (w AppstreamQuery) stacksFieldsToResponse() backend.DataResponse {
         "StreamingExperienceSettings",
         "UserSettings",
     }
+    fields = append(fields, samm.NewSammStack(w.svc, nil, 0).NestedFieldList()...)
     return fieldsToResponse(fields, fieldlist)
 }
 
@@ -223,7 +418,7 @@ func (a AppstreamQuery) stacksToResponse() backend.DataResponse {
         }
     } else {
         cacheItem := a.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -231,7 +426,7 @@ func (a AppstreamQuery) stacksToResponse() backend.DataResponse {
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, a.queryData.FieldList, a.refID)
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
     if err != nil {
         response.Error = err
         return response
@@ -258,6 +453,7 @@ func (w AppstreamQuery) sessionsFieldsToResponse() backend.DataResponse {
         "State",
         "UserId",
     }
+    fields = append(fields, samm.NewSammSession(w.svc, nil, 0).NestedFieldList()...)
     return fieldsToResponse(fields, fieldlist)
 }
 
@@ -274,7 +470,7 @@ func (a AppstreamQuery) sessionsToResponse() backend.DataResponse {
         }
     } else {
         cacheItem := a.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -282,7 +478,7 @@ func (a AppstreamQuery) sessionsToResponse() backend.DataResponse {
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, a.queryData.FieldList, a.refID)
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
     if err != nil {
         response.Error = err
         return response
@@ -303,6 +499,7 @@ func (w AppstreamQuery) directoryConfigsFieldsToResponse() backend.DataResponse
         "OrganizationalUnitDistinguishedNames",
         "ServiceAccountCredentials",
     }
+    fields = append(fields, samm.NewSammDirectoryConfigs(w.svc, nil, 0).NestedFieldList()...)
     return fieldsToResponse(fields, fieldlist)
 }
 
@@ -319,7 +516,7 @@ func (a AppstreamQuery) directoryConfigsToResponse() backend.DataResponse {
         }
     } else {
         cacheItem := a.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -327,7 +524,7 @@ func (a AppstreamQuery) directoryConfigsToResponse() backend.DataResponse {
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, a.queryData.FieldList, a.refID)
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
     if err != nil {
         response.Error = err
         return response
@@ -356,7 +553,7 @@ func (a AppstreamQuery) associatedStacksToResponse() backend.DataResponse {
         response.Error = err
     }
 
-    frame, err := CreateFrame(sw, a.queryData.FieldList, a.refID)
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
     if err != nil {
         response.Error = err
         return response
@@ -387,7 +584,7 @@ func (a AppstreamQuery) associatedFleetsToResponse() backend.DataResponse {
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, a.queryData.FieldList, a.refID)
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
     if err != nil {
         response.Error = err
         return response
@@ -398,38 +595,974 @@ func (a AppstreamQuery) associatedFleetsToResponse() backend.DataResponse {
     return response
 }
 
-func (a AppstreamQuery) expireSession() ([]byte, error) {
-    filter := appstream.ExpireSessionInput{ 
-        SessionId: &a.actionData.Id,
+/* ************************************************************* */
+
+func (w AppstreamQuery) usersFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "Arn",
+        "AuthenticationType",
+        "CreatedTime",
+        "Enabled",
+        "FirstName",
+        "LastName",
+        "Status",
+        "UserName",
     }
-    _, err := a.svc.ExpireSession(&filter)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) usersToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammUser(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "appstream.User"
+    if len(a.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := a.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
     if err != nil {
-        return []byte{}, err
+        response.Error = err
+        return response
     }
-    return []byte("{ \"message\": \"Session is being Expired\" }"), nil
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
 }
+/* ************************************************************* */
 
-func (a AppstreamQuery) echo() ([]byte, error) {
-    return []byte(fmt.Sprintf("{ \"message\": \"You requested an echo from: %s\" }", a.actionData.Id)), nil
+func (w AppstreamQuery) userStackAssociationsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AuthenticationType",
+        "SendEmailNotification",
+        "StackName",
+        "UserName",
+    }
+    return fieldsToResponse(fields, fieldlist)
 }
 
-func (a AppstreamQuery) listActions() ([]byte, error) {
-    isAdmin := a.role == "Admin"
-    actions := []SammAwsAction {
-        {
-            Action: "expire-session",
-            DisplayName: "Expire Session",
-            Disabled: !(isAdmin),
-            Confirm: true,
-        },
-        /*
-        {
-            Action: "echo",
-            DisplayName: "Echo",
-            Disabled: false,
-            Confirm: true,
-        },
-        */
+func (a AppstreamQuery) userStackAssociationsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammUserStackAssociation(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    /* Not cached: the associations are always filtered by stack or user */
+    err := sw.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
+    }
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w AppstreamQuery) imagesFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "Applications",
+        "AppstreamAgentVersion",
+        "Arn",
+        "BaseImageArn",
+        "CreatedTime",
+        "Description",
+        "DisplayName",
+        "ImageBuilderName",
+        "ImageBuilderSupported",
+        "ImageErrors",
+        "ImagePermissions",
+        "Name",
+        "Platform",
+        "PublicBaseImageReleasedDate",
+        "State",
+        "StateChangeReason",
+        "Visibility",
+    }
+    fields = append(fields, samm.NewSammImage(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) imagesToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammImage(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "appstream.Image"
+    if len(a.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := a.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w AppstreamQuery) imageBuildersFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AccessEndpoints",
+        "AppstreamAgentVersion",
+        "Arn",
+        "CreatedTime",
+        "Description",
+        "DisplayName",
+        "DomainJoinInfo",
+        "EnableDefaultInternetAccess",
+        "IamRoleArn",
+        "ImageArn",
+        "ImageBuilderErrors",
+        "InstanceType",
+        "Name",
+        "NetworkAccessConfiguration",
+        "Platform",
+        "State",
+        "StateChangeReason",
+        "VpcConfig",
+    }
+    fields = append(fields, samm.NewSammImageBuilder(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) imageBuildersToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammImageBuilder(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "appstream.ImageBuilder"
+    if len(a.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := a.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w AppstreamQuery) applicationsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AppBlockArn",
+        "Arn",
+        "CreatedTime",
+        "Description",
+        "DisplayName",
+        "Enabled",
+        "IconS3Location",
+        "IconURL",
+        "InstanceFamilies",
+        "LaunchParameters",
+        "LaunchPath",
+        "Metadata",
+        "Name",
+        "Platforms",
+        "WorkingDirectory",
+    }
+    fields = append(fields, samm.NewSammApplication(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) applicationsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammApplication(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "appstream.Application"
+    if len(a.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := a.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w AppstreamQuery) appBlocksFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AppBlockErrors",
+        "Arn",
+        "CreatedTime",
+        "Description",
+        "DisplayName",
+        "Name",
+        "PackagingType",
+        "PostSetupScriptDetails",
+        "SetupScriptDetails",
+        "SourceS3Location",
+        "State",
+    }
+    fields = append(fields, samm.NewSammAppBlock(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) appBlocksToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammAppBlock(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "appstream.AppBlock"
+    if len(a.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := a.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w AppstreamQuery) applicationFleetAssociationsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "ApplicationArn",
+        "FleetName",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) applicationFleetAssociationsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammApplicationFleetAssociation(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "appstream.ApplicationFleetAssociation"
+    if len(a.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := a.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w AppstreamQuery) entitlementsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AppVisibility",
+        "Attributes",
+        "CreatedTime",
+        "Description",
+        "LastModifiedTime",
+        "Name",
+        "StackName",
+    }
+    fields = append(fields, samm.NewSammEntitlement(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) entitlementsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammEntitlement(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    err := sw.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
+    }
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w AppstreamQuery) entitledApplicationsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "ApplicationIdentifier",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (a AppstreamQuery) entitledApplicationsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammEntitledApplication(a.svc, a.queryData.FilterConditions, a.queryData.Limit)
+
+    err := sw.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
+    }
+
+    frame, err := CreateFrame(sw, a.queryData, a.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+
+func (a AppstreamQuery) expireSession() ([]byte, error) {
+    filter := appstream.ExpireSessionInput{ 
+        SessionId: &a.actionData.Id,
+    }
+    _, err := a.svc.ExpireSession(&filter)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Session is being Expired\" }"), nil
+}
+
+func (a AppstreamQuery) startFleet() ([]byte, error) {
+    input := appstream.StartFleetInput{
+        Name: &a.actionData.Id,
+    }
+    _, err := a.svc.StartFleet(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Fleet is being Started\" }"), nil
+}
+
+func (a AppstreamQuery) stopFleet() ([]byte, error) {
+    input := appstream.StopFleetInput{
+        Name: &a.actionData.Id,
+    }
+    _, err := a.svc.StopFleet(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Fleet is being Stopped\" }"), nil
+}
+
+func (a AppstreamQuery) updateFleet() ([]byte, error) {
+    if len(a.actionData.Parameters) == 0 {
+        return []byte{}, errors.New("No parameters to update")
+    }
+    input := appstream.UpdateFleetInput{
+        Name: &a.actionData.Id,
+    }
+    for parameter, value := range a.actionData.Parameters {
+        temp, err := strconv.ParseInt(value, 10, 64)
+        if err != nil {
+            return []byte{}, fmt.Errorf("Invalid value for parameter %s: %s", parameter, value)
+        }
+        switch parameter {
+        case "DesiredCapacity":
+            input.SetComputeCapacity(&appstream.ComputeCapacity{ DesiredInstances: &temp })
+        case "MaxUserDurationInSeconds":
+            input.SetMaxUserDurationInSeconds(temp)
+        case "IdleDisconnectTimeoutInSeconds":
+            input.SetIdleDisconnectTimeoutInSeconds(temp)
+        case "DisconnectTimeoutInSeconds":
+            input.SetDisconnectTimeoutInSeconds(temp)
+        default:
+            return []byte{}, fmt.Errorf("Invalid parameter for update-fleet: %s", parameter)
+        }
+    }
+    _, err := a.svc.UpdateFleet(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Fleet is being Updated\" }"), nil
+}
+
+func (a AppstreamQuery) associateFleet() ([]byte, error) {
+    fleetName, stackName, err := a.fleetStackParameters()
+    if err != nil {
+        return []byte{}, err
+    }
+    input := appstream.AssociateFleetInput{
+        FleetName: &fleetName,
+        StackName: &stackName,
+    }
+    _, err = a.svc.AssociateFleet(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Fleet has been Associated\" }"), nil
+}
+
+func (a AppstreamQuery) disassociateFleet() ([]byte, error) {
+    fleetName, stackName, err := a.fleetStackParameters()
+    if err != nil {
+        return []byte{}, err
+    }
+    input := appstream.DisassociateFleetInput{
+        FleetName: &fleetName,
+        StackName: &stackName,
+    }
+    _, err = a.svc.DisassociateFleet(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Fleet has been Disassociated\" }"), nil
+}
+
+type SammAwsStreamingURL struct {
+    Message      string    `json:"message"`
+    StreamingURL string    `json:"url"`
+    Expires      time.Time `json:"expires"`
+}
+
+func (a AppstreamQuery) createStreamingURL() ([]byte, error) {
+    fleetName, stackName, err := a.fleetStackParameters()
+    if err != nil {
+        return []byte{}, err
+    }
+    userId := a.actionData.Parameters["UserId"]
+    if userId == "" {
+        return []byte{}, errors.New("Parameter UserId is mandatory")
+    }
+    input := appstream.CreateStreamingURLInput{
+        FleetName: &fleetName,
+        StackName: &stackName,
+        UserId: &userId,
+    }
+    if value, ok := a.actionData.Parameters["Validity"]; ok {
+        validity, err := strconv.ParseInt(value, 10, 64)
+        if err != nil {
+            return []byte{}, fmt.Errorf("Invalid value for parameter Validity: %s", value)
+        }
+        input.SetValidity(validity)
+    }
+    output, err := a.svc.CreateStreamingURL(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return json.Marshal(SammAwsStreamingURL{
+        Message: "Streaming URL has been Created",
+        StreamingURL: aws.StringValue(output.StreamingURL),
+        Expires: aws.TimeValue(output.Expires),
+    })
+}
+
+func (a AppstreamQuery) fleetStackParameters() (string, string, error) {
+    fleetName := a.actionData.Parameters["FleetName"]
+    stackName := a.actionData.Parameters["StackName"]
+    if fleetName == "" {
+        return "", "", errors.New("Parameter FleetName is mandatory")
+    }
+    if stackName == "" {
+        return "", "", errors.New("Parameter StackName is mandatory")
+    }
+    return fleetName, stackName, nil
+}
+
+func (a AppstreamQuery) authenticationType() string {
+    if value, ok := a.actionData.Parameters["AuthenticationType"]; ok && value != "" {
+        return value
+    }
+    return appstream.AuthenticationTypeUserpool
+}
+
+func (a AppstreamQuery) createUser() ([]byte, error) {
+    if a.actionData.Id == "" {
+        return []byte{}, errors.New("UserName is mandatory")
+    }
+    input := appstream.CreateUserInput{
+        UserName: &a.actionData.Id,
+    }
+    input.SetAuthenticationType(a.authenticationType())
+    if value, ok := a.actionData.Parameters["FirstName"]; ok {
+        input.SetFirstName(value)
+    }
+    if value, ok := a.actionData.Parameters["LastName"]; ok {
+        input.SetLastName(value)
+    }
+    if value, ok := a.actionData.Parameters["MessageAction"]; ok {
+        input.SetMessageAction(value)
+    }
+    _, err := a.svc.CreateUser(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"User has been Created\" }"), nil
+}
+
+func (a AppstreamQuery) enableUser() ([]byte, error) {
+    input := appstream.EnableUserInput{
+        UserName: &a.actionData.Id,
+    }
+    input.SetAuthenticationType(a.authenticationType())
+    _, err := a.svc.EnableUser(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"User has been Enabled\" }"), nil
+}
+
+func (a AppstreamQuery) disableUser() ([]byte, error) {
+    input := appstream.DisableUserInput{
+        UserName: &a.actionData.Id,
+    }
+    input.SetAuthenticationType(a.authenticationType())
+    _, err := a.svc.DisableUser(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"User has been Disabled\" }"), nil
+}
+
+func (a AppstreamQuery) deleteUser() ([]byte, error) {
+    input := appstream.DeleteUserInput{
+        UserName: &a.actionData.Id,
+    }
+    input.SetAuthenticationType(a.authenticationType())
+    _, err := a.svc.DeleteUser(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"User has been Deleted\" }"), nil
+}
+
+/* Id can hold a comma separated list of user names. */
+func (a AppstreamQuery) userStackAssociations() ([]*appstream.UserStackAssociation, error) {
+    stackName := a.actionData.Parameters["StackName"]
+    if stackName == "" {
+        return nil, errors.New("Parameter StackName is mandatory")
+    }
+    authenticationType := a.authenticationType()
+    sendEmailNotification := a.actionData.Parameters["SendEmailNotification"] == "true"
+    associations := []*appstream.UserStackAssociation{}
+    for _, userName := range strings.Split(a.actionData.Id, ",") {
+        userName = strings.TrimSpace(userName)
+        if userName == "" {
+            continue
+        }
+        associations = append(associations, &appstream.UserStackAssociation{
+            AuthenticationType: aws.String(authenticationType),
+            SendEmailNotification: aws.Bool(sendEmailNotification),
+            StackName: aws.String(stackName),
+            UserName: aws.String(userName),
+        })
+    }
+    if len(associations) == 0 {
+        return nil, errors.New("UserName is mandatory")
+    }
+    return associations, nil
+}
+
+func userStackAssociationErrors(errs []*appstream.UserStackAssociationError) error {
+    if len(errs) == 0 {
+        return nil
+    }
+    temp := make([]string, len(errs))
+    for i, e := range errs {
+        temp[i] = fmt.Sprintf("%s: %s", 
+            aws.StringValue(e.UserStackAssociation.UserName), 
+            aws.StringValue(e.ErrorMessage))
+    }
+    return errors.New(strings.Join(temp, ", "))
+}
+
+func (a AppstreamQuery) associateUserStack() ([]byte, error) {
+    associations, err := a.userStackAssociations()
+    if err != nil {
+        return []byte{}, err
+    }
+    output, err := a.svc.BatchAssociateUserStack(&appstream.BatchAssociateUserStackInput{
+        UserStackAssociations: associations,
+    })
+    if err != nil {
+        return []byte{}, err
+    }
+    if err = userStackAssociationErrors(output.Errors); err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Users have been Assigned to Stack\" }"), nil
+}
+
+func (a AppstreamQuery) disassociateUserStack() ([]byte, error) {
+    associations, err := a.userStackAssociations()
+    if err != nil {
+        return []byte{}, err
+    }
+    output, err := a.svc.BatchDisassociateUserStack(&appstream.BatchDisassociateUserStackInput{
+        UserStackAssociations: associations,
+    })
+    if err != nil {
+        return []byte{}, err
+    }
+    if err = userStackAssociationErrors(output.Errors); err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Users have been Unassigned from Stack\" }"), nil
+}
+
+func (a AppstreamQuery) startImageBuilder() ([]byte, error) {
+    input := appstream.StartImageBuilderInput{
+        Name: &a.actionData.Id,
+    }
+    _, err := a.svc.StartImageBuilder(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Image Builder is being Started\" }"), nil
+}
+
+func (a AppstreamQuery) stopImageBuilder() ([]byte, error) {
+    input := appstream.StopImageBuilderInput{
+        Name: &a.actionData.Id,
+    }
+    _, err := a.svc.StopImageBuilder(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Image Builder is being Stopped\" }"), nil
+}
+
+func (a AppstreamQuery) deleteImageBuilder() ([]byte, error) {
+    input := appstream.DeleteImageBuilderInput{
+        Name: &a.actionData.Id,
+    }
+    _, err := a.svc.DeleteImageBuilder(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Image Builder is being Deleted\" }"), nil
+}
+
+func (a AppstreamQuery) createImageBuilderStreamingURL() ([]byte, error) {
+    input := appstream.CreateImageBuilderStreamingURLInput{
+        Name: &a.actionData.Id,
+    }
+    if value, ok := a.actionData.Parameters["Validity"]; ok {
+        validity, err := strconv.ParseInt(value, 10, 64)
+        if err != nil {
+            return []byte{}, fmt.Errorf("Invalid value for parameter Validity: %s", value)
+        }
+        input.SetValidity(validity)
+    }
+    output, err := a.svc.CreateImageBuilderStreamingURL(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return json.Marshal(SammAwsStreamingURL{
+        Message: "Streaming URL has been Created",
+        StreamingURL: aws.StringValue(output.StreamingURL),
+        Expires: aws.TimeValue(output.Expires),
+    })
+}
+
+func (a AppstreamQuery) echo() ([]byte, error) {
+    return []byte(fmt.Sprintf("{ \"message\": \"You requested an echo from: %s\" }", a.actionData.Id)), nil
+}
+
+func (a AppstreamQuery) listActions() ([]byte, error) {
+    switch a.actionData.Resource {
+    case "fleet":
+        return a.listFleetActions()
+    case "stack":
+        return a.listStackActions()
+    case "user":
+        return a.listUserActions()
+    case "image-builder":
+        return a.listImageBuilderActions()
+    }
+    isAdmin := a.role == "Admin"
+    actions := []SammAwsAction {
+        {
+            Action: "expire-session",
+            DisplayName: "Expire Session",
+            Disabled: !(isAdmin),
+            Confirm: true,
+        },
+        /*
+        {
+            Action: "echo",
+            DisplayName: "Echo",
+            Disabled: false,
+            Confirm: true,
+        },
+        */
+    }
+    return json.Marshal(actions)
+}
+
+func (a AppstreamQuery) listFleetActions() ([]byte, error) {
+    fleetName := a.actionData.Id
+    sf := samm.NewSammFleet(a.svc, []models.FilterCondition{{Property: "FleetName", Value: fleetName}}, 1)
+    err := sf.UpdateElements([]interface{}{}, nil, false)
+    if err != nil || sf.Len() != 1 {
+        return []byte{}, fmt.Errorf("Unable to get information for FleetName=\"%s\".", fleetName)
+    }
+    fleet := sf.At(0).(*appstream.Fleet)
+    isAdmin := a.role == "Admin"
+    actions := []SammAwsAction {
+        {
+            Action: "start-fleet",
+            DisplayName: "Start Fleet",
+            Disabled: !(isAdmin && aws.StringValue(fleet.State) == "STOPPED"),
+            Confirm: true,
+        },
+        {
+            Action: "stop-fleet",
+            DisplayName: "Stop Fleet",
+            Disabled: !(isAdmin && (
+                aws.StringValue(fleet.State) == "RUNNING" ||
+                aws.StringValue(fleet.State) == "STARTING")),
+            Confirm: true,
+        },
+        {
+            Action: "update-fleet",
+            DisplayName: "Update Fleet",
+            Disabled: !(isAdmin && (
+                aws.StringValue(fleet.State) == "RUNNING" ||
+                aws.StringValue(fleet.State) == "STOPPED")),
+            Confirm: true,
+        },
+        {
+            Action: "create-streaming-url",
+            DisplayName: "Create Streaming URL",
+            Disabled: !(isAdmin && aws.StringValue(fleet.State) == "RUNNING"),
+            Confirm: false,
+        },
+    }
+    return json.Marshal(actions)
+}
+
+func (a AppstreamQuery) listStackActions() ([]byte, error) {
+    isAdmin := a.role == "Admin"
+    actions := []SammAwsAction {
+        {
+            Action: "associate-fleet",
+            DisplayName: "Associate Fleet",
+            Disabled: !isAdmin,
+            Confirm: true,
+        },
+        {
+            Action: "disassociate-fleet",
+            DisplayName: "Disassociate Fleet",
+            Disabled: !isAdmin,
+            Confirm: true,
+        },
+        {
+            Action: "create-streaming-url",
+            DisplayName: "Create Streaming URL",
+            Disabled: !isAdmin,
+            Confirm: false,
+        },
+    }
+    return json.Marshal(actions)
+}
+
+func (a AppstreamQuery) listUserActions() ([]byte, error) {
+    userName := a.actionData.Id
+    user, err := a.findUser(userName)
+    if err != nil {
+        return []byte{}, fmt.Errorf("Unable to get information for UserName=\"%s\".", userName)
+    }
+    isAdmin := a.role == "Admin"
+    exists := user != nil
+    enabled := exists && aws.BoolValue(user.Enabled)
+    actions := []SammAwsAction {
+        {
+            Action: "create-user",
+            DisplayName: "Create User",
+            Disabled: !(isAdmin && !exists),
+            Confirm: true,
+        },
+        {
+            Action: "enable-user",
+            DisplayName: "Enable User",
+            Disabled: !(isAdmin && exists && !enabled),
+            Confirm: true,
+        },
+        {
+            Action: "disable-user",
+            DisplayName: "Disable User",
+            Disabled: !(isAdmin && enabled),
+            Confirm: true,
+        },
+        {
+            Action: "delete-user",
+            DisplayName: "Delete User",
+            Disabled: !(isAdmin && exists),
+            Confirm: true,
+        },
+        {
+            Action: "associate-user-stack",
+            DisplayName: "Assign User to Stack",
+            Disabled: !(isAdmin && exists),
+            Confirm: true,
+        },
+        {
+            Action: "disassociate-user-stack",
+            DisplayName: "Unassign User from Stack",
+            Disabled: !(isAdmin && exists),
+            Confirm: true,
+        },
+    }
+    return json.Marshal(actions)
+}
+
+/* findUser returns the user of the pool or nil. DescribeUsers can't filter
+ * by user name, the pages are read until the user is found. */
+func (a AppstreamQuery) findUser(userName string) (*appstream.User, error) {
+    input := &appstream.DescribeUsersInput{
+        AuthenticationType: aws.String(a.authenticationType()),
+    }
+    for {
+        output, err := a.svc.DescribeUsers(input)
+        if err != nil {
+            return nil, err
+        }
+        for _, user := range output.Users {
+            if aws.StringValue(user.UserName) == userName {
+                return user, nil
+            }
+        }
+        if output.NextToken == nil {
+            return nil, nil
+        }
+        input.SetNextToken(*output.NextToken)
+    }
+}
+
+func (a AppstreamQuery) listImageBuilderActions() ([]byte, error) {
+    name := a.actionData.Id
+    sb := samm.NewSammImageBuilder(a.svc, []models.FilterCondition{{Property: "Name", Value: name}}, 1)
+    err := sb.UpdateElements([]interface{}{}, nil, false)
+    if err != nil || sb.Len() != 1 {
+        return []byte{}, fmt.Errorf("Unable to get information for ImageBuilder=\"%s\".", name)
+    }
+    builder := sb.At(0).(*appstream.ImageBuilder)
+    isAdmin := a.role == "Admin"
+    actions := []SammAwsAction {
+        {
+            Action: "start-image-builder",
+            DisplayName: "Start Image Builder",
+            Disabled: !(isAdmin && aws.StringValue(builder.State) == "STOPPED"),
+            Confirm: true,
+        },
+        {
+            Action: "stop-image-builder",
+            DisplayName: "Stop Image Builder",
+            Disabled: !(isAdmin && aws.StringValue(builder.State) == "RUNNING"),
+            Confirm: true,
+        },
+        {
+            Action: "delete-image-builder",
+            DisplayName: "Delete Image Builder",
+            Disabled: !(isAdmin && (
+                aws.StringValue(builder.State) == "STOPPED" ||
+                aws.StringValue(builder.State) == "FAILED")),
+            Confirm: true,
+        },
+        {
+            Action: "create-image-builder-streaming-url",
+            DisplayName: "Connect to Image Builder",
+            Disabled: !(isAdmin && aws.StringValue(builder.State) == "RUNNING"),
+            Confirm: false,
+        },
     }
     return json.Marshal(actions)
 }
@@ -0,0 +1,239 @@
+package plugin
+
+import (
+    "errors"
+    "fmt"
+    "encoding/json"
+    "strings"
+    "time"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/cloudtrail"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend"
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+
+    "github.com/samana-group/sammaws/pkg/models"
+    "github.com/samana-group/sammaws/pkg/samm"
+)
+
+/* Lifecycle events shown as annotations when the query has no condition on
+ * EventName. */
+var lifecycleEvents = map[string][]string{
+    "workspaces.amazonaws.com": {
+        "CreateWorkspaces",
+        "MigrateWorkspace",
+        "ModifyWorkspaceProperties",
+        "ModifyWorkspaceState",
+        "RebootWorkspaces",
+        "RebuildWorkspaces",
+        "RestoreWorkspace",
+        "StartWorkspaces",
+        "StopWorkspaces",
+        "TerminateWorkspaces",
+    },
+    "appstream.amazonaws.com": {
+        "AssociateFleet",
+        "CreateFleet",
+        "DeleteFleet",
+        "DisassociateFleet",
+        "ExpireSession",
+        "StartFleet",
+        "StopFleet",
+        "UpdateFleet",
+        "UpdateStack",
+    },
+}
+
+type CloudTrailQuery struct {
+    svc        *cloudtrail.CloudTrail
+    queryData  models.QueryModel
+    dataSource *Datasource
+    refID      string
+    timeRange  backend.TimeRange
+}
+
+func NewCloudTrailQuery(queryData models.QueryModel, dataSource *Datasource, refID string, timeRange backend.TimeRange) CloudTrailQuery {
+    return CloudTrailQuery{
+        svc: cloudtrail.New(dataSource.AwsSession),
+        queryData: queryData,
+        dataSource: dataSource,
+        refID: refID,
+        timeRange: timeRange,
+    }
+}
+
+func (c CloudTrailQuery) QueryData() backend.DataResponse {
+    switch c.queryData.ServiceQuery {
+    case "LookupEvents":
+        return c.lookupEventsToResponse()
+    case "LookupEventsFields":
+        return c.lookupEventsFieldsToResponse()
+
+    case "LookupEventsAnnotations":
+        return c.annotationsToResponse()
+    case "LookupEventsLogs":
+        return c.lookupEventsLogsToResponse()
+    }
+    return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("Not Implemented service_query %v", c.queryData.ServiceQuery))
+}
+
+func (c CloudTrailQuery) QueryVariable() ([]byte, error) {
+    response := c.QueryData()
+    if response.Error != nil {
+        return []byte{}, response.Error
+    }
+    return json.Marshal(response.Frames)
+}
+
+func (c CloudTrailQuery) ListActions() ([]byte, error) {
+    return json.Marshal([]SammAwsAction{})
+}
+
+func (c CloudTrailQuery) CallAction() ([]byte, error) {
+    return []byte{}, errors.New("Not Implemented")
+}
+
+func (c CloudTrailQuery) lookupEventsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AccessKeyId",
+        "CloudTrailEvent",
+        "ErrorCode",
+        "ErrorMessage",
+        "EventId",
+        "EventName",
+        "EventSource",
+        "EventTime",
+        "FleetName",
+        "ReadOnly",
+        "ResourceNames",
+        "ResourceTypes",
+        "SourceIPAddress",
+        "StackName",
+        "Username",
+        "WorkspaceIds",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+/* Events depend on the time range, they are not cached. */
+func (c CloudTrailQuery) lookupEventsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    se := samm.NewSammCloudTrailEvent(c.svc, c.queryData.FilterConditions, c.queryData.Limit, c.timeRange.From, c.timeRange.To)
+    err := se.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
+    }
+
+    frame, err := CreateFrame(se, c.queryData, c.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+
+/* lookupEvents returns the write events of the WorkSpaces and AppStream
+ * sources matching the filter conditions, in time order. Without a
+ * condition on EventName only the lifecycle events are kept, the whole time
+ * range is read as the limit of the table would cut them off. */
+func (c CloudTrailQuery) lookupEvents() ([]*cloudtrail.Event, error) {
+    byName := false
+    for _, filterCondition := range c.queryData.FilterConditions {
+        byName = byName || filterCondition.Property == "EventName"
+    }
+    events := []*cloudtrail.Event{}
+    for _, source := range []string{ "workspaces.amazonaws.com", "appstream.amazonaws.com" } {
+        lifecycle := map[string]bool{}
+        for _, name := range lifecycleEvents[source] {
+            lifecycle[name] = true
+        }
+        filterConditions := append([]models.FilterCondition{
+            {Property: "EventSource", Value: source},
+            {Property: "ReadOnly", Value: "false"},
+        }, c.queryData.FilterConditions...)
+        se := samm.NewSammCloudTrailEvent(c.svc, filterConditions, 0, c.timeRange.From, c.timeRange.To)
+        err := se.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            return nil, err
+        }
+        rows, err := samm.FilterElements(&se, se.ClientFilterConditions())
+        if err != nil {
+            return nil, err
+        }
+        for _, row := range rows {
+            event := se.At(row).(*cloudtrail.Event)
+            if byName || lifecycle[aws.StringValue(event.EventName)] {
+                events = append(events, event)
+            }
+        }
+    }
+    samm.SortEvents(events)
+    return events, nil
+}
+
+/* eventText describes an event: who called what on which resources. */
+func eventText(event *cloudtrail.Event) string {
+    detail := samm.EventDetail(event)
+    resources := samm.EventWorkspaceIds(event)
+    if fleet := samm.EventResourceName(event, "Fleet"); fleet != "" {
+        resources = append(resources, fleet)
+    }
+    if stack := samm.EventResourceName(event, "Stack"); stack != "" {
+        resources = append(resources, stack)
+    }
+    text := fmt.Sprintf("%s by %s", aws.StringValue(event.EventName), aws.StringValue(event.Username))
+    if len(resources) > 0 {
+        text += " on " + strings.Join(resources, ", ")
+    }
+    if detail.ErrorCode != "" {
+        text += fmt.Sprintf(" failed: %s %s", detail.ErrorCode, detail.ErrorMessage)
+    }
+    return text
+}
+
+/* annotationsToResponse returns an annotation frame: time, title, text and
+ * tags, the tags being comma separated as Grafana splits them. */
+func (c CloudTrailQuery) annotationsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    events, err := c.lookupEvents()
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    times := make([]time.Time, len(events))
+    titles := make([]string, len(events))
+    texts := make([]string, len(events))
+    tags := make([]string, len(events))
+    for i, event := range events {
+        workspaceIds := samm.EventWorkspaceIds(event)
+        fleet := samm.EventResourceName(event, "Fleet")
+
+        times[i] = aws.TimeValue(event.EventTime)
+        titles[i] = aws.StringValue(event.EventName)
+        texts[i] = eventText(event)
+
+        eventTags := []string{ aws.StringValue(event.EventName), "user:" + aws.StringValue(event.Username) }
+        for _, id := range workspaceIds {
+            eventTags = append(eventTags, "WorkspaceId:" + id)
+        }
+        if fleet != "" {
+            eventTags = append(eventTags, "Fleet:" + fleet)
+        }
+        tags[i] = strings.Join(eventTags, ",")
+    }
+
+    frame := data.NewFrame(c.refID,
+        data.NewField("time", nil, times),
+        data.NewField("title", nil, titles),
+        data.NewField("text", nil, texts),
+        data.NewField("tags", nil, tags),
+    )
+    response.Frames = append(response.Frames, frame)
+    return response
+}
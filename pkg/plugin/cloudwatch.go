@@ -0,0 +1,301 @@
+package plugin
+
+import (
+    "errors"
+    "fmt"
+    "encoding/json"
+    "time"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/appstream"
+    "github.com/aws/aws-sdk-go/service/cloudwatch"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+
+    "github.com/samana-group/sammaws/pkg/models"
+    "github.com/samana-group/sammaws/pkg/samm"
+)
+
+/* The cloudwatch service runs GetMetricData for the WorkSpaces and AppStream
+ * namespaces. The dimension values come from the inventory, filtered by the
+ * filter conditions of the query, and each value gives a time series. */
+const maxMetricDataQueries = 500
+
+type cloudwatchNamespace struct {
+    namespace string
+    dimensions []string
+    metrics []string
+}
+
+var cloudwatchNamespaces = map[string]cloudwatchNamespace{
+    "WorkSpacesMetrics": {
+        namespace: "AWS/WorkSpaces",
+        dimensions: []string{ "WorkspaceId", "DirectoryId" },
+        metrics: []string{
+            "Available",
+            "CPUUsage",
+            "ConnectionAttempt",
+            "ConnectionFailure",
+            "ConnectionSuccess",
+            "CurrentSessionLength",
+            "InSessionLatency",
+            "Maintenance",
+            "MemoryUsage",
+            "RootVolumeDiskUsage",
+            "SessionDisconnect",
+            "SessionLaunchTime",
+            "Stopped",
+            "UDPPacketLossRate",
+            "Unhealthy",
+            "UpTime",
+            "UserConnected",
+            "UserVolumeDiskUsage",
+        },
+    },
+    "AppStreamMetrics": {
+        namespace: "AWS/AppStream",
+        dimensions: []string{ "Fleet" },
+        metrics: []string{
+            "ActualCapacity",
+            "AvailableCapacity",
+            "CapacityUtilization",
+            "DesiredCapacity",
+            "InUseCapacity",
+            "InsufficientCapacityError",
+            "PendingCapacity",
+            "RunningCapacity",
+        },
+    },
+}
+
+type CloudwatchQuery struct {
+    svc        *cloudwatch.CloudWatch
+    queryData  models.QueryModel
+    dataSource *Datasource
+    refID      string
+    timeRange  backend.TimeRange
+    interval   time.Duration
+}
+
+func NewCloudwatchQuery(queryData models.QueryModel, dataSource *Datasource, refID string, timeRange backend.TimeRange, interval time.Duration) CloudwatchQuery {
+    return CloudwatchQuery{
+        svc: cloudwatch.New(dataSource.AwsSession),
+        queryData: queryData,
+        dataSource: dataSource,
+        refID: refID,
+        timeRange: timeRange,
+        interval: interval,
+    }
+}
+
+func (c CloudwatchQuery) QueryData() backend.DataResponse {
+    switch c.queryData.ServiceQuery {
+    case "WorkSpacesMetrics", "AppStreamMetrics":
+        return c.metricDataToResponse(cloudwatchNamespaces[c.queryData.ServiceQuery])
+    case "WorkSpacesMetricsFields", "AppStreamMetricsFields":
+        fieldlist := []string{ "Label", "Value" }
+        namespace := cloudwatchNamespaces[c.queryData.ServiceQuery[:len(c.queryData.ServiceQuery) - len("Fields")]]
+        return fieldsToResponse(namespace.metrics, fieldlist)
+    }
+    return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("Not Implemented service_query %v", c.queryData.ServiceQuery))
+}
+
+func (c CloudwatchQuery) QueryVariable() ([]byte, error) {
+    response := c.QueryData()
+    if response.Error != nil {
+        return []byte{}, response.Error
+    }
+    return json.Marshal(response.Frames)
+}
+
+func (c CloudwatchQuery) ListActions() ([]byte, error) {
+    return json.Marshal([]SammAwsAction{})
+}
+
+func (c CloudwatchQuery) CallAction() ([]byte, error) {
+    return []byte{}, errors.New("Not Implemented")
+}
+
+func (c CloudwatchQuery) metricDataToResponse(namespace cloudwatchNamespace) backend.DataResponse {
+    var response backend.DataResponse
+    if c.queryData.MetricName == "" {
+        return backend.ErrDataResponse(backend.StatusBadRequest, "Parameter 'metricName' is mandatory")
+    }
+    dimension := c.queryData.Dimension
+    if dimension == "" {
+        dimension = namespace.dimensions[0]
+    }
+    valid := false
+    for _, d := range namespace.dimensions {
+        valid = valid || d == dimension
+    }
+    if !valid {
+        return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("Invalid dimension %s for %s", dimension, namespace.namespace))
+    }
+    statistic := c.queryData.Statistic
+    if statistic == "" {
+        statistic = "Average"
+    }
+
+    values, err := c.dimensionValues(dimension)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    queries := make([]*cloudwatch.MetricDataQuery, len(values))
+    for i, value := range values {
+        queries[i] = &cloudwatch.MetricDataQuery{
+            Id: aws.String(fmt.Sprintf("m%d", i)),
+            Label: aws.String(value),
+            MetricStat: &cloudwatch.MetricStat{
+                Metric: &cloudwatch.Metric{
+                    Namespace: aws.String(namespace.namespace),
+                    MetricName: aws.String(c.queryData.MetricName),
+                    Dimensions: []*cloudwatch.Dimension{
+                        { Name: aws.String(dimension), Value: aws.String(value) },
+                    },
+                },
+                Period: aws.Int64(c.period()),
+                Stat: aws.String(statistic),
+            },
+        }
+    }
+
+    results, err := c.getMetricData(queries)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    for i, value := range values {
+        result := results[fmt.Sprintf("m%d", i)]
+        times := []time.Time{}
+        points := []*float64{}
+        if result != nil {
+            for j, timestamp := range result.Timestamps {
+                times = append(times, aws.TimeValue(timestamp))
+                points = append(points, result.Values[j])
+            }
+        }
+        frame := data.NewFrame(c.refID,
+            data.NewField("Time", nil, times),
+            data.NewField(c.queryData.MetricName, data.Labels{dimension: value}, points),
+        )
+        frame.Meta = &data.FrameMeta{
+            Type: data.FrameTypeTimeSeriesMulti,
+            TypeVersion: data.FrameTypeVersion{0, 1},
+        }
+        response.Frames = append(response.Frames, frame)
+    }
+    return response
+}
+
+/* period is the query interval rounded up to a multiple of a minute, of 5
+ * minutes when the start of the range is older than 15 days and of an hour
+ * when it is older than 63 days, CloudWatch keeps no finer data points. */
+func (c CloudwatchQuery) period() int64 {
+    step := int64(60)
+    age := time.Since(c.timeRange.From)
+    if age > 63 * 24 * time.Hour {
+        step = 3600
+    } else if age > 15 * 24 * time.Hour {
+        step = 300
+    }
+    seconds := int64(c.interval / time.Second)
+    if seconds < step {
+        return step
+    }
+    return (seconds + step - 1) / step * step
+}
+
+/* getMetricData runs the queries by chunks of 500 and merges the pages of
+ * each result. */
+func (c CloudwatchQuery) getMetricData(queries []*cloudwatch.MetricDataQuery) (map[string]*cloudwatch.MetricDataResult, error) {
+    results := map[string]*cloudwatch.MetricDataResult{}
+    for start := 0; start < len(queries); start += maxMetricDataQueries {
+        end := min(start + maxMetricDataQueries, len(queries))
+        input := &cloudwatch.GetMetricDataInput{
+            MetricDataQueries: queries[start:end],
+            StartTime: aws.Time(c.timeRange.From),
+            EndTime: aws.Time(c.timeRange.To),
+            ScanBy: aws.String(cloudwatch.ScanByTimestampAscending),
+        }
+        err := c.svc.GetMetricDataPages(input, func(page *cloudwatch.GetMetricDataOutput, lastPage bool) bool {
+            for _, result := range page.MetricDataResults {
+                id := aws.StringValue(result.Id)
+                if merged, ok := results[id]; ok {
+                    merged.Timestamps = append(merged.Timestamps, result.Timestamps...)
+                    merged.Values = append(merged.Values, result.Values...)
+                } else {
+                    results[id] = result
+                }
+            }
+            return true
+        })
+        if err != nil {
+            return nil, err
+        }
+    }
+    return results, nil
+}
+
+/* dimensionValues returns the ids of the inventory elements matching the
+ * filter conditions. The inventory is read through the cache, the tags only
+ * when a condition refers to them. */
+func (c CloudwatchQuery) dimensionValues(dimension string) ([]string, error) {
+    var se updatableElement
+    var serviceKey string
+    var value func(interface{}) *string
+    var setTags func()
+    needsTags := samm.NeedsTags(nil, c.queryData.FilterConditions)
+    w := WorkspacesQuery{svc: workspaces.New(c.dataSource.AwsSession), dataSource: c.dataSource}
+    switch dimension {
+    case "WorkspaceId":
+        sw := samm.NewSammWorkspace(w.svc, nil, c.queryData.Limit)
+        se = &sw
+        serviceKey = "workspaces.Workspace"
+        value = func(e interface{}) *string { return e.(*workspaces.Workspace).WorkspaceId }
+        setTags = func() { sw.SetTags(w.resourceTags(sw.ResourceIds(), -1)) }
+    case "DirectoryId":
+        sd := samm.NewSammWorkspacesDirectory(w.svc, nil, c.queryData.Limit)
+        se = &sd
+        serviceKey = "workspaces.WorkspacesDirectory"
+        value = func(e interface{}) *string { return e.(*workspaces.WorkspaceDirectory).DirectoryId }
+        setTags = func() { sd.SetTags(w.resourceTags(sd.ResourceIds(), -1)) }
+    case "Fleet":
+        if needsTags {
+            return nil, fmt.Errorf("Tag filters are not supported for dimension %s.", dimension)
+        }
+        sf := samm.NewSammFleet(appstream.New(c.dataSource.AwsSession), nil, c.queryData.Limit)
+        se = &sf
+        serviceKey = "appstream.Fleet"
+        value = func(e interface{}) *string { return e.(*appstream.Fleet).Name }
+    default:
+        return nil, fmt.Errorf("Invalid dimension %s", dimension)
+    }
+
+    cacheItem := c.dataSource.Cache.Get(serviceKey)
+    err := se.UpdateElements(cacheItem.Load())
+    cacheItem.Update(se, err)
+    if err != nil {
+        return nil, err
+    }
+    if needsTags {
+        setTags()
+    }
+
+    rows, err := samm.FilterElements(se, c.queryData.FilterConditions)
+    if err != nil {
+        return nil, err
+    }
+    values := make([]string, 0, len(rows))
+    for _, row := range rows {
+        values = append(values, aws.StringValue(value(se.At(row))))
+    }
+    log.DefaultLogger.Debug("CloudWatch dimension values", "dimension", dimension, "count", len(values))
+    return values, nil
+}
@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+func TestCloudwatchPeriod(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		age      time.Duration
+		interval time.Duration
+		want     int64
+	}{
+		{time.Hour, 10 * time.Second, 60},
+		{time.Hour, 90 * time.Second, 120},
+		{16 * day, 90 * time.Second, 300},
+		{16 * day, 10 * time.Minute, 600},
+		{16 * day, 11 * time.Minute, 900},
+		{64 * day, 10 * time.Minute, 3600},
+		{64 * day, 90 * time.Minute, 7200},
+	}
+	for _, tt := range tests {
+		c := CloudwatchQuery{
+			timeRange: backend.TimeRange{From: time.Now().Add(-tt.age), To: time.Now()},
+			interval:  tt.interval,
+		}
+		if got := c.period(); got != tt.want {
+			t.Errorf("age %v, interval %v: got the period %d, want %d", tt.age, tt.interval, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package plugin
+
+import (
+    "encoding/json"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+    "github.com/jmespath/go-jmespath"
+
+    "github.com/samana-group/sammaws/pkg/samm"
+)
+
+/* A computed field is a field list entry of the form
+ *     name = expression
+ *     name:type = expression
+ * The JMESPath expression is evaluated against the raw AWS element. When the
+ * type (string, number, boolean, time or json) is not declared, it is inferred
+ * from the first value that is not null. */
+type computedField struct {
+    Name       string
+    Type       string
+    Expression *jmespath.JMESPath
+}
+
+/* Tag fields are never computed fields, a tag key may contain "=". */
+func isComputedField(entry string) bool {
+    return !samm.IsTagField(entry) && strings.Contains(entry, "=")
+}
+
+func parseComputedField(entry string) (computedField, error) {
+    before, after, _ := strings.Cut(entry, "=")
+    name, fieldType, _ := strings.Cut(strings.TrimSpace(before), ":")
+    field := computedField{
+        Name: strings.TrimSpace(name),
+        Type: strings.ToLower(strings.TrimSpace(fieldType)),
+    }
+    if field.Name == "" {
+        return field, fmt.Errorf("Missing name for computed field '%s'.", entry)
+    }
+    switch field.Type {
+    case "", "string", "number", "boolean", "time", "json":
+    default:
+        return field, fmt.Errorf("Invalid type '%s' for computed field %s.", field.Type, field.Name)
+    }
+    expression, err := jmespath.Compile(strings.TrimSpace(after))
+    if err != nil {
+        return field, fmt.Errorf("Invalid expression for computed field %s: %s", field.Name, err.Error())
+    }
+    field.Expression = expression
+    return field, nil
+}
+
+/* toGeneric converts an AWS structure to the generic maps and lists
+ * JMESPath works on, using the AWS attribute names as keys. */
+func toGeneric(element interface{}) (interface{}, error) {
+    raw, err := json.Marshal(element)
+    if err != nil {
+        return nil, err
+    }
+    var generic interface{}
+    err = json.Unmarshal(raw, &generic)
+    return generic, err
+}
+
+/* genericRows converts each row once, the computed fields of the field
+ * list are all evaluated against the same conversion. */
+func genericRows(elements samm.SammElement, rows []int) ([]interface{}, error) {
+    generic := make([]interface{}, len(rows))
+    for i, itemIndex := range rows {
+        var err error
+        generic[i], err = toGeneric(elements.At(itemIndex))
+        if err != nil {
+            return nil, err
+        }
+    }
+    return generic, nil
+}
+
+/* An expression failing on an element, for example length() of a missing
+ * list, gives a null value for that element only. */
+func (c computedField) evaluate(elements []interface{}) []interface{} {
+    values := make([]interface{}, len(elements))
+    for i, element := range elements {
+        value, err := c.Expression.Search(element)
+        if err != nil {
+            log.DefaultLogger.Debug("Unable to evaluate computed field", "field", c.Name, "error", err.Error())
+            continue
+        }
+        values[i] = value
+    }
+    return values
+}
+
+func (c computedField) inferType(values []interface{}) string {
+    if c.Type != "" {
+        return c.Type
+    }
+    for _, value := range values {
+        switch value.(type) {
+        case nil:
+            continue
+        case string:
+            return "string"
+        case float64:
+            return "number"
+        case bool:
+            return "boolean"
+        default:
+            return "json"
+        }
+    }
+    return "string"
+}
+
+func (c computedField) toField(values []interface{}) *data.Field {
+    switch c.inferType(values) {
+    case "number":
+        temp := make([]*float64, len(values))
+        for i, value := range values {
+            temp[i] = computedNumber(value)
+        }
+        return data.NewField(c.Name, nil, temp)
+    case "boolean":
+        temp := make([]*bool, len(values))
+        for i, value := range values {
+            if b, ok := value.(bool); ok {
+                temp[i] = &b
+            }
+        }
+        return data.NewField(c.Name, nil, temp)
+    case "time":
+        temp := make([]*time.Time, len(values))
+        for i, value := range values {
+            temp[i] = computedTime(value)
+        }
+        return data.NewField(c.Name, nil, temp)
+    }
+    temp := make([]*string, len(values))
+    for i, value := range values {
+        temp[i] = computedString(value)
+    }
+    return data.NewField(c.Name, nil, temp)
+}
+
+func computedNumber(value interface{}) *float64 {
+    switch v := value.(type) {
+    case float64:
+        return &v
+    case bool:
+        n := 0.0
+        if v {
+            n = 1.0
+        }
+        return &n
+    case string:
+        if n, err := strconv.ParseFloat(v, 64); err == nil {
+            return &n
+        }
+    }
+    return nil
+}
+
+func computedTime(value interface{}) *time.Time {
+    switch v := value.(type) {
+    case string:
+        if t, err := time.Parse(time.RFC3339, v); err == nil {
+            return &t
+        }
+    case float64:
+        t := time.UnixMilli(int64(v))
+        return &t
+    }
+    return nil
+}
+
+func computedString(value interface{}) *string {
+    switch v := value.(type) {
+    case nil:
+        return nil
+    case string:
+        return &v
+    }
+    raw, err := json.Marshal(value)
+    if err != nil {
+        return nil
+    }
+    s := string(raw)
+    return &s
+}
@@ -0,0 +1,185 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/samana-group/sammaws/pkg/models"
+	"github.com/samana-group/sammaws/pkg/samm"
+)
+
+func TestParseComputedField(t *testing.T) {
+	tests := []struct {
+		entry     string
+		name      string
+		fieldType string
+		wantErr   bool
+	}{
+		{entry: "Mode = WorkspaceProperties.RunningMode", name: "Mode"},
+		{entry: "Size:number = WorkspaceProperties.RootVolumeSizeGib", name: "Size", fieldType: "number"},
+		{entry: " Created : TIME = CreationTime", name: "Created", fieldType: "time"},
+		{entry: "Raw:json = ModificationStates", name: "Raw", fieldType: "json"},
+		{entry: " = State", wantErr: true},
+		{entry: ":number = State", wantErr: true},
+		{entry: "State:date = State", wantErr: true},
+		{entry: "State = [", wantErr: true},
+	}
+	for _, tt := range tests {
+		field, err := parseComputedField(tt.entry)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.entry)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", tt.entry, err)
+			continue
+		}
+		if field.Name != tt.name || field.Type != tt.fieldType || field.Expression == nil {
+			t.Errorf("%q: got name %q type %q", tt.entry, field.Name, field.Type)
+		}
+	}
+	if isComputedField("WorkspaceId") || !isComputedField("Id=WorkspaceId") {
+		t.Error("isComputedField must only accept entries with an expression")
+	}
+	if isComputedField("tag:Owner=Team") {
+		t.Error("a tag field whose key contains '=' is not a computed field")
+	}
+}
+
+func TestComputedFieldInferType(t *testing.T) {
+	tests := []struct {
+		fieldType string
+		values    []interface{}
+		want      string
+	}{
+		{values: []interface{}{nil, "a"}, want: "string"},
+		{values: []interface{}{nil, 2.0}, want: "number"},
+		{values: []interface{}{true}, want: "boolean"},
+		{values: []interface{}{map[string]interface{}{"a": 1.0}}, want: "json"},
+		{values: []interface{}{[]interface{}{"a"}}, want: "json"},
+		{values: []interface{}{nil, nil}, want: "string"},
+		{values: []interface{}{}, want: "string"},
+		{fieldType: "number", values: []interface{}{"12"}, want: "number"},
+	}
+	for _, tt := range tests {
+		c := computedField{Name: "c", Type: tt.fieldType}
+		if got := c.inferType(tt.values); got != tt.want {
+			t.Errorf("inferType(%v) = %s, want %s", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestComputedFieldToField(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		fieldType string
+		values    []interface{}
+		want      data.FieldType
+		check     func(field *data.Field) bool
+	}{
+		{
+			fieldType: "number",
+			values:    []interface{}{"12.5", true, "x", nil},
+			want:      data.FieldTypeNullableFloat64,
+			check: func(field *data.Field) bool {
+				return *field.At(0).(*float64) == 12.5 && *field.At(1).(*float64) == 1 &&
+					field.At(2).(*float64) == nil && field.At(3).(*float64) == nil
+			},
+		},
+		{
+			fieldType: "boolean",
+			values:    []interface{}{true, "true"},
+			want:      data.FieldTypeNullableBool,
+			check: func(field *data.Field) bool {
+				return *field.At(0).(*bool) && field.At(1).(*bool) == nil
+			},
+		},
+		{
+			fieldType: "time",
+			values:    []interface{}{created.Format(time.RFC3339), float64(created.UnixMilli()), "yesterday"},
+			want:      data.FieldTypeNullableTime,
+			check: func(field *data.Field) bool {
+				return field.At(0).(*time.Time).Equal(created) && field.At(1).(*time.Time).Equal(created) &&
+					field.At(2).(*time.Time) == nil
+			},
+		},
+		{
+			values: []interface{}{map[string]interface{}{"a": 1.0}, nil},
+			want:   data.FieldTypeNullableString,
+			check: func(field *data.Field) bool {
+				return *field.At(0).(*string) == `{"a":1}` && field.At(1).(*string) == nil
+			},
+		},
+	}
+	for _, tt := range tests {
+		c := computedField{Name: "c", Type: tt.fieldType}
+		field := c.toField(tt.values)
+		if field.Type() != tt.want {
+			t.Errorf("%s: field type %s, want %s", tt.fieldType, field.Type(), tt.want)
+			continue
+		}
+		if !tt.check(field) {
+			t.Errorf("%s: unexpected values for %v", tt.fieldType, tt.values)
+		}
+	}
+}
+
+func TestComputedFieldEvaluationErrors(t *testing.T) {
+	c, err := parseComputedField("Count = length(Items)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := c.evaluate([]interface{}{
+		map[string]interface{}{"Items": []interface{}{"a", "b"}},
+		map[string]interface{}{},
+		map[string]interface{}{"Items": 3.0},
+	})
+	if values[0] != 2.0 {
+		t.Errorf("expected 2 items, got %v", values[0])
+	}
+	if values[1] != nil || values[2] != nil {
+		t.Errorf("failing evaluations must give null values, got %v and %v", values[1], values[2])
+	}
+}
+
+func TestCreateFrameComputedFields(t *testing.T) {
+	sw := testWorkspaces("AVAILABLE", "STOPPED")
+	queryData := models.QueryModel{
+		FieldList: []string{"WorkspaceId", "Stopped:boolean = State == 'STOPPED'", "Lower = WorkspaceId"},
+	}
+	frame, err := CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped, _ := frame.FieldByName("Stopped")
+	if stopped == nil || *stopped.At(0).(*bool) || !*stopped.At(1).(*bool) {
+		t.Fatalf("unexpected computed values %v", stopped)
+	}
+	lower, _ := frame.FieldByName("Lower")
+	if lower == nil || *lower.At(1).(*string) != "ws-1" {
+		t.Fatalf("unexpected computed values %v", lower)
+	}
+
+	queryData.FieldList = []string{"Bad = length(", "WorkspaceId"}
+	if _, err := CreateFrame(sw, queryData, "A"); err == nil {
+		t.Fatal("an invalid expression must fail the query")
+	}
+}
+
+func TestCreateFrameTagFieldWithEquals(t *testing.T) {
+	sw := testWorkspaces("AVAILABLE")
+	sw.SetTags(samm.ResourceTags{"ws-0": {"cost=center": aws.String("42")}})
+	frame, err := CreateFrame(sw, models.QueryModel{FieldList: []string{"WorkspaceId", "tag:cost=center"}}, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	field, _ := frame.FieldByName("tag:cost=center")
+	if field == nil || aws.StringValue(field.At(0).(*string)) != "42" {
+		t.Fatalf("unexpected tag values %v", field)
+	}
+}
@@ -4,15 +4,20 @@ import (
     "context"
     "encoding/json"
     "net/http"
+    "path/filepath"
     "time"
     "fmt"
 
     "github.com/grafana/grafana-plugin-sdk-go/backend"
     "github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
-    //"github.com/grafana/grafana-plugin-sdk-go/backend/log"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
 
     "github.com/samana-group/sammaws/pkg/models"
     "github.com/samana-group/sammaws/pkg/cache"
+    "github.com/samana-group/sammaws/pkg/audit"
+    "github.com/samana-group/sammaws/pkg/inventory"
+    "github.com/samana-group/sammaws/pkg/pricing"
+    "github.com/samana-group/sammaws/pkg/tsstore"
 
     "github.com/aws/aws-sdk-go/aws"
     "github.com/aws/aws-sdk-go/aws/session"
@@ -29,6 +34,7 @@ import (
 var (
     _ backend.QueryDataHandler      = (*Datasource)(nil)
     _ backend.CheckHealthHandler    = (*Datasource)(nil)
+    _ backend.StreamHandler         = (*Datasource)(nil)
     _ instancemgmt.InstanceDisposer = (*Datasource)(nil)
 )
 
@@ -50,8 +56,16 @@ type SammAwsAction struct {
 
 type Datasource struct{
     AwsSession *session.Session
-    Cache cache.CacheMap
+    Cache *cache.CacheMap
     CacheDuration time.Duration
+    Audit *audit.AuditLog
+    Samples *tsstore.Store
+    Inventory *inventory.Store
+    Pricing *pricing.Table
+    /* Closed when the instance is disposed, stops the background tasks */
+    stop chan struct{}
+    streams *streamState
+    streamInterval time.Duration
 }
 
 // NewDatasource creates a new datasource instance.
@@ -84,6 +98,44 @@ func NewDatasource(_ context.Context, settings backend.DataSourceInstanceSetting
     d := Datasource{
         AwsSession: sess,
         Cache: cache.NewCacheMap(time.Duration(config.CacheSeconds) * time.Second),
+        Audit: audit.NewAuditLog(1000),
+        stop: make(chan struct{}),
+        streams: &streamState{snapshots: map[string]*streamSnapshot{}},
+        streamInterval: time.Duration(config.StreamIntervalSeconds) * time.Second,
+    }
+    if d.streamInterval == 0 {
+        d.streamInterval = defaultStreamInterval
+    } else if d.streamInterval < minStreamInterval {
+        d.streamInterval = minStreamInterval
+    }
+    retention := time.Duration(config.SampleRetentionDays) * 24 * time.Hour
+    if retention <= 0 {
+        retention = 30 * 24 * time.Hour
+    }
+    dir, dirErr := dataDir(config, settings.UID)
+    if len(config.Samplers) > 0 {
+        if dirErr != nil {
+            log.DefaultLogger.Error("Unable to open the sample store", "error", dirErr.Error())
+        } else if d.Samples, err = tsstore.NewStore(dir, retention); err != nil {
+            log.DefaultLogger.Error("Unable to open the sample store", "error", err.Error())
+        } else {
+            d.startSampling(config.Samplers)
+        }
+    }
+    if config.InventoryIntervalSeconds > 0 {
+        if dirErr != nil {
+            log.DefaultLogger.Error("Unable to open the inventory store", "error", dirErr.Error())
+        } else if d.Inventory, err = inventory.NewStore(filepath.Join(dir, "inventory"), retention); err != nil {
+            log.DefaultLogger.Error("Unable to open the inventory store", "error", err.Error())
+        } else {
+            d.startInventory(time.Duration(config.InventoryIntervalSeconds) * time.Second, d.stop)
+        }
+    }
+    if config.PricingFile != "" {
+        d.Pricing, err = pricing.Load(config.PricingFile, config.AutoStopHoursPerMonth)
+        if err != nil {
+            log.DefaultLogger.Error("Unable to load the pricing table", "error", err.Error())
+        }
     }
     return &d, nil
 }
@@ -116,6 +168,10 @@ func (d *Datasource) CallResource(ctx context.Context, req *backend.CallResource
 // be disposed and a new one will be created using NewSampleDatasource factory function.
 func (d *Datasource) Dispose() {
     // Clean up datasource instance resources.
+    if d.stop != nil {
+        close(d.stop)
+        d.stop = nil
+    }
 }
 
 
@@ -137,14 +193,24 @@ func (d *Datasource) QueryData(ctx context.Context, req *backend.QueryDataReques
             continue
         }
 
-        if (queryData.Service == "workspaces") {
-            query = NewWorkspacesQuery(queryData, models.ActionModel{}, d, "", q.RefID)
-
-        } else if queryData.Service == "appstream" {
-            query = NewAppstreamQuery(queryData, models.ActionModel{}, d, "", q.RefID)
-
+        if queryData.Service == "samples" {
+            response.Responses[q.RefID] = d.samplesToResponse(queryData, q.TimeRange, q.Interval, q.RefID)
+            continue
+        }
+        if queryData.Service == "changes" {
+            response.Responses[q.RefID] = d.changesToResponse(queryData, q.TimeRange, q.RefID)
+            continue
+        }
+        if queryData.Service == "audit" {
+            response.Responses[q.RefID] = d.auditToResponse(queryData, q.TimeRange, q.RefID)
+            continue
+        }
+        if queryData.Service == "cloudwatch" {
+            query = NewCloudwatchQuery(queryData, d, q.RefID, q.TimeRange, q.Interval)
+        } else if queryData.Service == "cloudtrail" {
+            query = NewCloudTrailQuery(queryData, d, q.RefID, q.TimeRange)
         } else {
-            query = NotImplemented{}
+            query = d.newQuery(queryData, models.ActionModel{}, "", q.RefID)
         }
 
         // save the response in a hashmap
@@ -155,6 +221,16 @@ func (d *Datasource) QueryData(ctx context.Context, req *backend.QueryDataReques
     return response, nil
 }
 
+func (d *Datasource) newQuery(queryData models.QueryModel, actionData models.ActionModel, role string, refID string) SammAwsQuery {
+    if (queryData.Service == "workspaces") {
+        return NewWorkspacesQuery(queryData, actionData, d, role, refID)
+
+    } else if queryData.Service == "appstream" {
+        return NewAppstreamQuery(queryData, actionData, d, role, refID)
+    }
+    return NotImplemented{}
+}
+
 // CheckHealth handles health checks sent from Grafana to the plugin.
 // The main use case for these health checks is the test button on the
 // datasource configuration page which allows users to verify that
@@ -227,6 +303,7 @@ func (d *Datasource) callAction(role string, ctx context.Context, req *backend.C
     }
 
     body, err := query.CallAction()
+    d.auditAction(req, role, actionData, err)
     if err != nil {
         return NewSammAwsResponse(err.Error(), http.StatusBadRequest, sender)
     }
@@ -236,6 +313,26 @@ func (d *Datasource) callAction(role string, ctx context.Context, req *backend.C
         })
 }
 
+func (d *Datasource) auditAction(req *backend.CallResourceRequest, role string, actionData models.ActionModel, actionErr error) {
+    if d.Audit == nil || actionData.Action == "list-actions" {
+        return
+    }
+    entry := audit.Entry{
+        Role: role,
+        Service: actionData.Service,
+        Action: actionData.Action,
+        Id: actionData.Id,
+        Parameters: actionData.Parameters,
+    }
+    if req.PluginContext.User != nil {
+        entry.Login = req.PluginContext.User.Login
+    }
+    if actionErr != nil {
+        entry.Error = actionErr.Error()
+    }
+    d.Audit.Record(entry)
+}
+
 func (d *Datasource) listActions(role string, ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
     var query SammAwsQuery
 
@@ -283,6 +380,14 @@ func (d *Datasource) queryVariable(role string, ctx context.Context, req *backen
 
     } else if queryData.Service == "appstream" {
         query = NewAppstreamQuery(queryData, models.ActionModel{}, d, role, "variable")
+
+    } else if queryData.Service == "cloudwatch" {
+        now := time.Now()
+        query = NewCloudwatchQuery(queryData, d, "variable", backend.TimeRange{From: now.Add(-time.Hour), To: now}, time.Minute)
+
+    } else if queryData.Service == "cloudtrail" {
+        now := time.Now()
+        query = NewCloudTrailQuery(queryData, d, "variable", backend.TimeRange{From: now.Add(-24 * time.Hour), To: now})
     } else {
         return NewSammAwsResponse(
             fmt.Sprintf("Unable to process query. query=%+v", queryData), 
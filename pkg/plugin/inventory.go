@@ -0,0 +1,165 @@
+package plugin
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/aws/aws-sdk-go/service/appstream"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+    "github.com/grafana/grafana-plugin-sdk-go/backend"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+
+    "github.com/samana-group/sammaws/pkg/inventory"
+    "github.com/samana-group/sammaws/pkg/models"
+    "github.com/samana-group/sammaws/pkg/samm"
+)
+
+/* When inventoryIntervalSeconds is set, the inventory of each resource is
+ * saved periodically with all its root attributes: nested fields are
+ * excepted as their root field holds the same value, and tag fields as they
+ * would need a DescribeTags call per element. The snapshots go through the cache like
+ * the queries and the samplers. The "changes" service returns the
+ * differences between the snapshots, service_query being the resource (the
+ * query name, e.g. DescribeWorkspaces) or empty for all the resources.
+ * DescribeIpGroups is not in the inventory, its rows are the rules of the
+ * groups and have no key. */
+const minInventoryInterval = 5 * time.Minute
+
+type inventoryResource struct {
+    service string
+    serviceQuery string
+    key string
+    element samm.SammElement
+    prototype interface{}
+}
+
+var inventoryResources = []inventoryResource{
+    {"workspaces", "DescribeWorkspaces", "WorkspaceId", samm.NewSammWorkspace(nil, nil, 0), &workspaces.Workspace{}},
+    {"workspaces", "DescribeWorkspaceDirectories", "DirectoryId", samm.NewSammWorkspacesDirectory(nil, nil, 0), &workspaces.WorkspaceDirectory{}},
+    {"workspaces", "DescribeWorkspaceBundles", "BundleId", samm.NewSammWorkspaceBundle(nil, nil, 0), &workspaces.WorkspaceBundle{}},
+    {"workspaces", "DescribeWorkspaceImages", "ImageId", samm.NewSammWorkspaceImage(nil, nil, 0), &workspaces.WorkspaceImage{}},
+    {"workspaces", "DescribeWorkspacesPools", "PoolId", samm.NewSammWorkspacesPool(nil, nil, 0), &workspaces.WorkspacesPool{}},
+    {"workspaces", "DescribeConnectionAliases", "AliasId", samm.NewSammConnectionAlias(nil, nil, 0), &workspaces.ConnectionAlias{}},
+    {"appstream", "DescribeFleets", "Name", samm.NewSammFleet(nil, nil, 0), &appstream.Fleet{}},
+    {"appstream", "DescribeStacks", "Name", samm.NewSammStack(nil, nil, 0), &appstream.Stack{}},
+    {"appstream", "DescribeImages", "Arn", samm.NewSammImage(nil, nil, 0), &appstream.Image{}},
+    {"appstream", "DescribeImageBuilders", "Name", samm.NewSammImageBuilder(nil, nil, 0), &appstream.ImageBuilder{}},
+    {"appstream", "DescribeApplications", "Arn", samm.NewSammApplication(nil, nil, 0), &appstream.Application{}},
+    {"appstream", "DescribeAppBlocks", "Arn", samm.NewSammAppBlock(nil, nil, 0), &appstream.AppBlock{}},
+    {"appstream", "DescribeDirectoryConfigs", "DirectoryName", samm.NewSammDirectoryConfigs(nil, nil, 0), &appstream.DirectoryConfig{}},
+}
+
+func isInventoryResource(serviceQuery string) bool {
+    for _, resource := range inventoryResources {
+        if resource.serviceQuery == serviceQuery {
+            return true
+        }
+    }
+    return false
+}
+
+func (d *Datasource) startInventory(interval time.Duration, stop chan struct{}) {
+    if interval < minInventoryInterval {
+        interval = minInventoryInterval
+    }
+    go func() {
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+        d.snapshotInventory()
+        for {
+            select {
+            case <-stop:
+                return
+            case <-ticker.C:
+                d.snapshotInventory()
+            }
+        }
+    }()
+}
+
+func (d *Datasource) snapshotInventory() {
+    now := time.Now()
+    for _, resource := range inventoryResources {
+        snapshot, err := d.inventorySnapshot(resource, now)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to snapshot the inventory", "resource", resource.serviceQuery, "error", err.Error())
+            continue
+        }
+        changes, err := d.Inventory.Save(resource.serviceQuery, snapshot)
+        if err != nil {
+            log.DefaultLogger.Error("Unable to save the inventory", "resource", resource.serviceQuery, "error", err.Error())
+            continue
+        }
+        log.DefaultLogger.Debug("Inventory saved", "resource", resource.serviceQuery, "rows", len(snapshot.Rows), "changes", len(changes))
+    }
+    if err := d.Inventory.Prune(now); err != nil {
+        log.DefaultLogger.Error("Unable to prune the inventory", "error", err.Error())
+    }
+}
+
+/* inventorySnapshot runs the query of the resource with the root
+ * attributes of its element. */
+func (d *Datasource) inventorySnapshot(resource inventoryResource, now time.Time) (inventory.Snapshot, error) {
+    snapshot := inventory.Snapshot{Time: now, Rows: map[string]map[string]string{}}
+    queryData := models.QueryModel{
+        Service: resource.service,
+        ServiceQuery: resource.serviceQuery,
+        FieldList: samm.AttributeNames(resource.element, resource.prototype),
+    }
+    response := d.newQuery(queryData, models.ActionModel{}, "", "inventory").QueryData()
+    if response.Error != nil {
+        return snapshot, response.Error
+    }
+    frame := response.Frames[0]
+    keyField, _ := frame.FieldByName(resource.key)
+    if keyField == nil {
+        return snapshot, nil
+    }
+    for i := 0; i < keyField.Len(); i++ {
+        row := map[string]string{}
+        for _, field := range frame.Fields {
+            if value := samm.ValueString(samm.FieldValue(field, i)); value != "" {
+                row[field.Name] = value
+            }
+        }
+        snapshot.Rows[samm.ValueString(samm.FieldValue(keyField, i))] = row
+    }
+    return snapshot, nil
+}
+
+func (d *Datasource) changesToResponse(queryData models.QueryModel, timeRange backend.TimeRange, refID string) backend.DataResponse {
+    var response backend.DataResponse
+    if d.Inventory == nil {
+        return backend.ErrDataResponse(backend.StatusInternal, "Inventory store not available")
+    }
+    resources := []string{}
+    if queryData.ServiceQuery != "" {
+        if !isInventoryResource(queryData.ServiceQuery) {
+            return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("Not Implemented service_query %v", queryData.ServiceQuery))
+        }
+        resources = append(resources, queryData.ServiceQuery)
+    }
+    changes, err := d.Inventory.Changes(resources, timeRange.From, timeRange.To)
+    if err != nil {
+        return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+    }
+
+    frame := data.NewFrame(refID,
+        data.NewField("Time", nil, []time.Time{}),
+        data.NewField("Resource", nil, []string{}),
+        data.NewField("Key", nil, []string{}),
+        data.NewField("Change", nil, []string{}),
+        data.NewField("Field", nil, []string{}),
+        data.NewField("OldValue", nil, []string{}),
+        data.NewField("NewValue", nil, []string{}),
+    )
+    frame.Meta = &data.FrameMeta{
+        PreferredVisualization: "table",
+    }
+    for _, change := range changes {
+        frame.AppendRow(change.Time, change.Resource, change.Key, change.Change, change.Field, change.Old, change.New)
+    }
+    response.Frames = append(response.Frames, frame)
+    return response
+}
@@ -0,0 +1,26 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/samana-group/sammaws/pkg/samm"
+)
+
+func TestInventoryFieldLists(t *testing.T) {
+	for _, resource := range inventoryResources {
+		names := samm.AttributeNames(resource.element, resource.prototype)
+		hasKey := false
+		for _, name := range names {
+			hasKey = hasKey || name == resource.key
+			if samm.IsNestedField(name) || samm.IsTagField(name) {
+				t.Errorf("%s: unexpected field %s", resource.serviceQuery, name)
+			}
+			if _, ok := resource.element.AttributeType(name); !ok {
+				t.Errorf("%s: unknown attribute %s", resource.serviceQuery, name)
+			}
+		}
+		if !hasKey {
+			t.Errorf("%s: the key %s is not in the fields %v", resource.serviceQuery, resource.key, names)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package plugin
+
+import (
+    "encoding/json"
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/cloudtrail"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend"
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+
+    "github.com/samana-group/sammaws/pkg/audit"
+    "github.com/samana-group/sammaws/pkg/models"
+    "github.com/samana-group/sammaws/pkg/samm"
+)
+
+/* Log queries return a logs frame, newest line first. The text search is
+ * case insensitive and applies to the whole event. When more lines are
+ * available, the token to pass as nextToken is in the custom metadata of
+ * the frame. */
+const defaultLogLimit = 100
+
+type logLine struct {
+    time     time.Time
+    body     string
+    severity string
+    id       string
+    labels   map[string]string
+}
+
+type logsMeta struct {
+    NextToken string `json:"nextToken,omitempty"`
+}
+
+func logFrame(refID string, lines []logLine, nextToken string) *data.Frame {
+    timestamps := make([]time.Time, len(lines))
+    bodies := make([]string, len(lines))
+    severities := make([]string, len(lines))
+    ids := make([]string, len(lines))
+    labels := make([]json.RawMessage, len(lines))
+    for i, line := range lines {
+        timestamps[i] = line.time
+        bodies[i] = line.body
+        severities[i] = line.severity
+        ids[i] = line.id
+        labels[i], _ = json.Marshal(line.labels)
+    }
+    frame := data.NewFrame(refID,
+        data.NewField("timestamp", nil, timestamps),
+        data.NewField("body", nil, bodies),
+        data.NewField("severity", nil, severities),
+        data.NewField("id", nil, ids),
+        data.NewField("labels", nil, labels),
+    )
+    frame.Meta = &data.FrameMeta{
+        Type: data.FrameTypeLogLines,
+        TypeVersion: data.FrameTypeVersion{0, 0},
+        PreferredVisualization: data.VisTypeLogs,
+        Custom: logsMeta{NextToken: nextToken},
+    }
+    return frame
+}
+
+func matchesSearch(search string, text string) bool {
+    return search == "" || strings.Contains(strings.ToLower(text), strings.ToLower(search))
+}
+
+func logLimit(queryData models.QueryModel) int {
+    if queryData.Limit <= 0 {
+        return defaultLogLimit
+    }
+    return queryData.Limit
+}
+
+/* eventLogLine formats a CloudTrail event, failed calls being errors. */
+func eventLogLine(event *cloudtrail.Event) logLine {
+    labels := map[string]string{
+        "EventName": aws.StringValue(event.EventName),
+        "EventSource": aws.StringValue(event.EventSource),
+        "Username": aws.StringValue(event.Username),
+    }
+    if workspaceIds := samm.EventWorkspaceIds(event); len(workspaceIds) > 0 {
+        labels["WorkspaceId"] = strings.Join(workspaceIds, ",")
+    }
+    if fleet := samm.EventResourceName(event, "Fleet"); fleet != "" {
+        labels["Fleet"] = fleet
+    }
+    severity := "info"
+    if samm.EventDetail(event).ErrorCode != "" {
+        severity = "error"
+    }
+    return logLine{
+        time: aws.TimeValue(event.EventTime),
+        body: eventText(event),
+        severity: severity,
+        id: aws.StringValue(event.EventId),
+        labels: labels,
+    }
+}
+
+/* lookupEventsLogsToResponse reads pages of events until the limit is
+ * reached, starting from the nextToken of the query. The whole pages are
+ * kept so that no event is skipped by the next request. */
+func (c CloudTrailQuery) lookupEventsLogsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    limit := logLimit(c.queryData)
+    var nextToken *string
+    if c.queryData.NextToken != "" {
+        nextToken = aws.String(c.queryData.NextToken)
+    }
+    lines := []logLine{}
+    for {
+        se := samm.NewSammCloudTrailEvent(c.svc, c.queryData.FilterConditions, limit - len(lines), c.timeRange.From, c.timeRange.To)
+        err := se.UpdateElements([]interface{}{}, nextToken, false)
+        if err != nil {
+            response.Error = err
+            return response
+        }
+        rows, err := samm.FilterElements(&se, se.ClientFilterConditions())
+        if err != nil {
+            response.Error = err
+            return response
+        }
+        for _, row := range rows {
+            event := se.At(row).(*cloudtrail.Event)
+            if matchesSearch(c.queryData.Search, aws.StringValue(event.CloudTrailEvent)) {
+                lines = append(lines, eventLogLine(event))
+            }
+        }
+        nextToken = se.NextToken()
+        if nextToken == nil || len(lines) >= limit {
+            break
+        }
+    }
+    response.Frames = append(response.Frames, logFrame(c.refID, lines, aws.StringValue(nextToken)))
+    return response
+}
+
+/* auditToResponse returns the recorded actions of the time range, newest
+ * first. The token is the sequence number of the last returned entry, so
+ * that the entries recorded meanwhile do not shift the next page. */
+func (d *Datasource) auditToResponse(queryData models.QueryModel, timeRange backend.TimeRange, refID string) backend.DataResponse {
+    var response backend.DataResponse
+    if d.Audit == nil {
+        return backend.ErrDataResponse(backend.StatusInternal, "Audit log not available")
+    }
+    if queryData.ServiceQuery != "ActionAudit" {
+        return backend.ErrDataResponse(backend.StatusBadRequest, fmt.Sprintf("Not Implemented service_query %v", queryData.ServiceQuery))
+    }
+    var before uint64
+    if queryData.NextToken != "" {
+        var err error
+        before, err = strconv.ParseUint(queryData.NextToken, 10, 64)
+        if err != nil {
+            return backend.ErrDataResponse(backend.StatusBadRequest, "Invalid nextToken")
+        }
+    }
+
+    entries := []audit.Entry{}
+    for _, entry := range d.Audit.Entries() {
+        if before > 0 && entry.Seq >= before {
+            continue
+        }
+        if entry.Time.Before(timeRange.From) || entry.Time.After(timeRange.To) {
+            continue
+        }
+        text, _ := json.Marshal(entry)
+        if matchesSearch(queryData.Search, string(text)) {
+            entries = append(entries, entry)
+        }
+    }
+    sort.SliceStable(entries, func(a, b int) bool { return entries[a].Seq > entries[b].Seq })
+
+    lines := []logLine{}
+    end := min(logLimit(queryData), len(entries))
+    for _, entry := range entries[:end] {
+        body := fmt.Sprintf("%s (%s) %s %s %s", entry.Login, entry.Role, entry.Service, entry.Action, entry.Id)
+        severity := "info"
+        if entry.Error != "" {
+            body += " failed: " + entry.Error
+            severity = "error"
+        }
+        lines = append(lines, logLine{
+            time: entry.Time,
+            body: body,
+            severity: severity,
+            id: fmt.Sprintf("%d-%s-%s", entry.Time.UnixNano(), entry.Action, entry.Id),
+            labels: map[string]string{
+                "login": entry.Login,
+                "role": entry.Role,
+                "service": entry.Service,
+                "action": entry.Action,
+                "id": entry.Id,
+            },
+        })
+    }
+    nextToken := ""
+    if end < len(entries) {
+        nextToken = strconv.FormatUint(entries[end - 1].Seq, 10)
+    }
+    response.Frames = append(response.Frames, logFrame(refID, lines, nextToken))
+    return response
+}
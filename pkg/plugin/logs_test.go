@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/samana-group/sammaws/pkg/audit"
+	"github.com/samana-group/sammaws/pkg/models"
+)
+
+func auditPage(t *testing.T, ds *Datasource, timeRange backend.TimeRange, nextToken string) ([]string, string) {
+	t.Helper()
+	queryData := models.QueryModel{ServiceQuery: "ActionAudit", Limit: 2, NextToken: nextToken}
+	response := ds.auditToResponse(queryData, timeRange, "A")
+	if response.Error != nil {
+		t.Fatal(response.Error)
+	}
+	frame := response.Frames[0]
+	field, _ := frame.FieldByName("id")
+	ids := []string{}
+	for i := 0; i < field.Len(); i++ {
+		ids = append(ids, field.At(i).(string))
+	}
+	return ids, frame.Meta.Custom.(logsMeta).NextToken
+}
+
+func TestAuditPaging(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	ds := &Datasource{Audit: audit.NewAuditLog(100)}
+	for i, id := range []string{"ws-1", "ws-2", "ws-3"} {
+		ds.Audit.Record(audit.Entry{Time: now.Add(time.Duration(i) * time.Minute), Action: "reboot", Id: id})
+	}
+	timeRange := backend.TimeRange{From: now.Add(-time.Hour), To: now.Add(time.Hour)}
+
+	ids, nextToken := auditPage(t, ds, timeRange, "")
+	if len(ids) != 2 || !strings.HasSuffix(ids[0], "-ws-3") || nextToken == "" {
+		t.Fatalf("expected a full first page, got %v %q", ids, nextToken)
+	}
+
+	/* an action recorded between the pages must not shift the next one */
+	ds.Audit.Record(audit.Entry{Time: now.Add(3 * time.Minute), Action: "reboot", Id: "ws-4"})
+	next, nextToken := auditPage(t, ds, timeRange, nextToken)
+	if len(next) != 1 || !strings.HasSuffix(next[0], "-ws-1") || nextToken != "" {
+		t.Fatalf("expected the oldest entry only, got %v %q", next, nextToken)
+	}
+
+	queryData := models.QueryModel{ServiceQuery: "ActionAudit", NextToken: "-1"}
+	if response := ds.auditToResponse(queryData, timeRange, "A"); response.Error == nil {
+		t.Fatal("an invalid token must fail the query")
+	}
+}
@@ -0,0 +1,187 @@
+package plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/appstream"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/samana-group/sammaws/pkg/models"
+	"github.com/samana-group/sammaws/pkg/samm"
+)
+
+func testWorkspaces(states ...string) samm.SammWorkspace {
+	return cachedWorkspaces(nil, states...)
+}
+
+/* cachedWorkspaces loads the workspaces the way a valid cache does, the
+ * filter conditions are then all applied client side. */
+func cachedWorkspaces(filterConditions []models.FilterCondition, states ...string) samm.SammWorkspace {
+	sw := samm.NewSammWorkspace(nil, filterConditions, 0)
+	elements := []interface{}{}
+	for i, state := range states {
+		elements = append(elements, &workspaces.Workspace{
+			WorkspaceId: aws.String(fmt.Sprintf("ws-%d", i)),
+			State:       aws.String(state),
+		})
+	}
+	sw.UpdateElements(elements, nil, true)
+	return sw
+}
+
+func testFleets(available map[string]int64) samm.SammFleet {
+	sf := samm.NewSammFleet(nil, nil, 0)
+	elements := []interface{}{}
+	for name, count := range available {
+		elements = append(elements, &appstream.Fleet{
+			Name:                  aws.String(name),
+			ComputeCapacityStatus: &appstream.ComputeCapacityStatus{Available: aws.Int64(count)},
+		})
+	}
+	sf.UpdateElements(elements, nil, true)
+	return sf
+}
+
+/* checkNumericWide applies the rules server side expressions use to read a
+ * numeric wide frame: one row, only number fields, unique series. */
+func checkNumericWide(t *testing.T, frame *data.Frame) {
+	t.Helper()
+	if frame.Meta == nil || frame.Meta.Type != data.FrameTypeNumericWide {
+		t.Fatalf("frame type must be %s", data.FrameTypeNumericWide)
+	}
+	if !frame.Meta.Type.IsNumeric() {
+		t.Fatal("frame type must be of the numeric kind")
+	}
+	if rows, _ := frame.RowLen(); len(frame.Fields) > 0 && rows != 1 {
+		t.Fatalf("numeric wide frame must have one row, got %d", rows)
+	}
+	series := map[string]bool{}
+	for _, field := range frame.Fields {
+		if !field.Type().Numeric() {
+			t.Fatalf("field %s is not numeric: %s", field.Name, field.Type())
+		}
+		key := field.Name + field.Labels.String()
+		if series[key] {
+			t.Fatalf("duplicate series %s", key)
+		}
+		series[key] = true
+	}
+}
+
+/* evaluateThreshold reduces each series to its value and returns the state
+ * of an alert rule "value > threshold" per label set. */
+func evaluateThreshold(t *testing.T, frame *data.Frame, above bool, threshold float64) map[string]bool {
+	t.Helper()
+	firing := map[string]bool{}
+	for _, field := range frame.Fields {
+		value, err := field.NullableFloatAt(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value == nil {
+			continue
+		}
+		if above {
+			firing[field.Labels.String()] = *value > threshold
+		} else {
+			firing[field.Labels.String()] = *value < threshold
+		}
+	}
+	return firing
+}
+
+func TestNumericSingleValue(t *testing.T) {
+	queryData := models.QueryModel{
+		Format:           "numeric",
+		FilterConditions: []models.FilterCondition{{Property: "State", Value: "UNHEALTHY"}},
+	}
+	sw := cachedWorkspaces(queryData.FilterConditions, "AVAILABLE", "UNHEALTHY", "UNHEALTHY", "STOPPED")
+
+	frame, err := CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNumericWide(t, frame)
+	if len(frame.Fields) != 1 || frame.Fields[0].Labels != nil {
+		t.Fatalf("expected a single unlabelled value, got %d fields", len(frame.Fields))
+	}
+	value, _ := frame.Fields[0].NullableFloatAt(0)
+	if value == nil || *value != 2 {
+		t.Fatalf("expected 2 unhealthy workspaces, got %v", value)
+	}
+	if firing := evaluateThreshold(t, frame, true, 5); firing[""] {
+		t.Fatal("2 unhealthy workspaces must not fire a > 5 rule")
+	}
+}
+
+func TestNumericLabelledSeries(t *testing.T) {
+	sw := testWorkspaces("AVAILABLE", "UNHEALTHY", "AVAILABLE", "STOPPED", "AVAILABLE")
+	queryData := models.QueryModel{
+		Format:  "numeric",
+		GroupBy: []string{"State"},
+	}
+
+	frame, err := CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNumericWide(t, frame)
+	if len(frame.Fields) != 3 {
+		t.Fatalf("expected 3 series, got %d", len(frame.Fields))
+	}
+	firing := evaluateThreshold(t, frame, true, 2)
+	if !firing[data.Labels{"State": "AVAILABLE"}.String()] {
+		t.Fatal("AVAILABLE count 3 must fire a > 2 rule")
+	}
+	if firing[data.Labels{"State": "STOPPED"}.String()] {
+		t.Fatal("STOPPED count 1 must not fire a > 2 rule")
+	}
+}
+
+func TestNumericFleetCapacity(t *testing.T) {
+	sf := testFleets(map[string]int64{"fleet-a": 1, "fleet-b": 8})
+	queryData := models.QueryModel{
+		Format:       "numeric",
+		GroupBy:      []string{"Name"},
+		Aggregations: []models.Aggregation{{Function: "sum", Property: "ComputeCapacityStatus.Available", Alias: "Available"}},
+	}
+
+	frame, err := CreateFrame(sf, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNumericWide(t, frame)
+	firing := evaluateThreshold(t, frame, false, 2)
+	if !firing[data.Labels{"Name": "fleet-a"}.String()] || firing[data.Labels{"Name": "fleet-b"}.String()] {
+		t.Fatalf("only fleet-a must fire an available < 2 rule, got %v", firing)
+	}
+}
+
+func TestNumericArrowRoundTrip(t *testing.T) {
+	sw := testWorkspaces("AVAILABLE", "UNHEALTHY")
+	queryData := models.QueryModel{
+		Format:  "numeric",
+		GroupBy: []string{"State"},
+	}
+
+	frame, err := CreateFrame(sw, queryData, "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	/* Frames reach the server side expressions encoded as arrow */
+	encoded, err := data.Frames{frame}.MarshalArrow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := data.UnmarshalArrowFrames(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkNumericWide(t, decoded[0])
+	if decoded[0].Meta.TypeVersion != (data.FrameTypeVersion{0, 1}) {
+		t.Fatalf("unexpected type version %s", decoded[0].Meta.TypeVersion)
+	}
+}
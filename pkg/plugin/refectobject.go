@@ -90,16 +90,16 @@ func (w ReflectObject) AddFrameFields(frame *data.Frame, fieldlist []string) err
         if w.isString {
             field.Append(w.valueReflect.String())
         } else {
-            v := w.valueReflect.FieldByName(fieldName).Pointer()
+            v := w.valueReflect.FieldByName(fieldName).Interface()
             switch w.fieldTypes[fieldName] {
             case "*string":
-                field.Append((*string)(unsafe.Pointer(v)))
+                field.Append(v.(*string))
             case "*float64":
-                field.Append((*float64)(unsafe.Pointer(v)))
+                field.Append(v.(*float64))
             case "*int64":
-                field.Append((*int64)(unsafe.Pointer(v)))
+                field.Append(v.(*int64))
             case "*time.Time":
-                field.Append((*time.Time)(unsafe.Pointer(v)))
+                field.Append(v.(*time.Time))
             case "[]*string":
                 sliceLen := w.valueReflect.FieldByName(fieldName).Len()
                 v1 := w.valueReflect.FieldByName(fieldName).Slice(0, sliceLen)
@@ -0,0 +1,170 @@
+package plugin
+
+import (
+    "errors"
+    "os"
+    "path/filepath"
+    "sort"
+    "time"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+
+    "github.com/samana-group/sammaws/pkg/models"
+    "github.com/samana-group/sammaws/pkg/tsstore"
+)
+
+/* Samplers run their aggregate query in the background and keep the
+ * numeric results in the local store. The "samples" service returns them
+ * as time series, service_query being the name of the sampler. */
+const (
+    minSampleInterval = 60 * time.Second
+    /* expired samples are removed when the sampler starts, then every hour */
+    samplePruneInterval = time.Hour
+)
+
+/* dataDir returns the directory where the datasource keeps its data:
+ * the dataPath setting, else the Grafana data path. The temp dir is not
+ * used, the samples would be lost on the next cleanup. */
+func dataDir(config *models.PluginSettings, uid string) (string, error) {
+    base := config.DataPath
+    if base == "" {
+        dataPath := os.Getenv("GF_PATHS_DATA")
+        if dataPath == "" {
+            return "", errors.New("No data path, set dataPath or GF_PATHS_DATA.")
+        }
+        base = filepath.Join(dataPath, "plugins-data", "sammaws")
+    }
+    return filepath.Join(base, uid), nil
+}
+
+func (d *Datasource) startSampling(samplers []models.SamplerSettings) {
+    for _, sampler := range samplers {
+        if sampler.Name == "" {
+            log.DefaultLogger.Warn("Sampler without name ignored")
+            continue
+        }
+        interval := time.Duration(sampler.IntervalSeconds) * time.Second
+        if interval < minSampleInterval {
+            interval = minSampleInterval
+        }
+        go d.runSampler(sampler, interval, d.stop)
+    }
+}
+
+func (d *Datasource) runSampler(sampler models.SamplerSettings, interval time.Duration, stop chan struct{}) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    pruneTicker := time.NewTicker(samplePruneInterval)
+    defer pruneTicker.Stop()
+    d.pruneSamples(sampler)
+    d.sample(sampler)
+    for {
+        select {
+        case <-stop:
+            return
+        case <-ticker.C:
+            d.sample(sampler)
+        case <-pruneTicker.C:
+            d.pruneSamples(sampler)
+        }
+    }
+}
+
+func (d *Datasource) pruneSamples(sampler models.SamplerSettings) {
+    err := d.Samples.Prune(sampler.Name, time.Now())
+    if err != nil {
+        log.DefaultLogger.Error("Unable to prune samples", "sampler", sampler.Name, "error", err.Error())
+    }
+}
+
+func (d *Datasource) sample(sampler models.SamplerSettings) {
+    now := time.Now()
+    queryData := sampler.Query
+    queryData.Format = "numeric"
+    response := d.newQuery(queryData, models.ActionModel{}, "", sampler.Name).QueryData()
+    if response.Error != nil {
+        log.DefaultLogger.Warn("Sampling failed", "sampler", sampler.Name, "error", response.Error.Error())
+        return
+    }
+    samples := []tsstore.Sample{}
+    for _, frame := range response.Frames {
+        for _, field := range frame.Fields {
+            if field.Len() == 0 {
+                continue
+            }
+            value, err := field.NullableFloatAt(0)
+            if err != nil {
+                continue
+            }
+            samples = append(samples, tsstore.Sample{
+                Time: now,
+                Name: field.Name,
+                Labels: field.Labels,
+                Value: value,
+            })
+        }
+    }
+    err := d.Samples.Append(sampler.Name, samples)
+    if err != nil {
+        log.DefaultLogger.Error("Unable to store samples", "sampler", sampler.Name, "error", err.Error())
+    }
+    log.DefaultLogger.Debug("Sampled", "sampler", sampler.Name, "series", len(samples))
+}
+
+/* samplesToResponse returns one frame per series. Within an interval only
+ * the last sample is kept. */
+func (d *Datasource) samplesToResponse(queryData models.QueryModel, timeRange backend.TimeRange, interval time.Duration, refID string) backend.DataResponse {
+    var response backend.DataResponse
+    if d.Samples == nil {
+        return backend.ErrDataResponse(backend.StatusInternal, "Sample store not available")
+    }
+    samples, err := d.Samples.Range(queryData.ServiceQuery, timeRange.From, timeRange.To)
+    if err != nil {
+        return backend.ErrDataResponse(backend.StatusInternal, err.Error())
+    }
+    if interval <= 0 {
+        interval = time.Minute
+    }
+
+    type series struct {
+        name    string
+        labels  data.Labels
+        times   []time.Time
+        values  []*float64
+    }
+    seriesByKey := map[string]*series{}
+    keys := []string{}
+    for _, sample := range samples {
+        key := tsstore.SeriesKey(sample)
+        s, ok := seriesByKey[key]
+        if !ok {
+            s = &series{name: sample.Name, labels: data.Labels(sample.Labels)}
+            seriesByKey[key] = s
+            keys = append(keys, key)
+        }
+        bucket := sample.Time.Truncate(interval)
+        if n := len(s.times); n > 0 && s.times[n - 1].Equal(bucket) {
+            s.values[n - 1] = sample.Value
+            continue
+        }
+        s.times = append(s.times, bucket)
+        s.values = append(s.values, sample.Value)
+    }
+    sort.Strings(keys)
+
+    for _, key := range keys {
+        s := seriesByKey[key]
+        frame := data.NewFrame(refID,
+            data.NewField("Time", nil, s.times),
+            data.NewField(s.name, s.labels, s.values),
+        )
+        frame.Meta = &data.FrameMeta{
+            Type: data.FrameTypeTimeSeriesMulti,
+            TypeVersion: data.FrameTypeVersion{0, 1},
+        }
+        response.Frames = append(response.Frames, frame)
+    }
+    return response
+}
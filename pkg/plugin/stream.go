@@ -0,0 +1,262 @@
+package plugin
+
+import (
+    "context"
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/appstream"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+
+    "github.com/samana-group/sammaws/pkg/models"
+    "github.com/samana-group/sammaws/pkg/samm"
+)
+
+/* Panels subscribe to the channels workspaces/state and appstream/sessions.
+ * Grafana runs a single RunStream per channel whatever the number of
+ * subscribers: it polls AWS, diffs the snapshot with the previous one and
+ * sends only the added, changed and removed rows. New subscribers get the
+ * last snapshot as initial data, reloaded when older than the interval. */
+const (
+    defaultStreamInterval = 30 * time.Second
+    minStreamInterval = 10 * time.Second
+)
+
+type streamSnapshot struct {
+    time   time.Time
+    fields []string
+    rows   map[string][]string
+}
+
+type streamState struct {
+    mu        sync.Mutex
+    snapshots map[string]*streamSnapshot
+}
+
+func (s *streamState) get(path string) *streamSnapshot {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.snapshots[path]
+}
+
+func (s *streamState) set(path string, snapshot *streamSnapshot) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.snapshots[path] = snapshot
+}
+
+/* newStreamSnapshot keeps the default fields of the elements as strings,
+ * by value of the key field, with the time it is taken. */
+func newStreamSnapshot(se samm.SammElement, key string) *streamSnapshot {
+    fieldList := []string{ key }
+    for _, name := range se.DefaultFieldList() {
+        if name != key {
+            fieldList = append(fieldList, name)
+        }
+    }
+    fields := make([]*data.Field, len(fieldList))
+    for f, name := range fieldList {
+        fieldType, _ := se.AttributeType(name)
+        fields[f] = data.NewField(name, nil, fieldType)
+        for i := 0; i < se.Len(); i++ {
+            se.AppendData(i, fields[f], name)
+        }
+    }
+    snapshot := &streamSnapshot{
+        time: time.Now(),
+        fields: fieldList,
+        rows: map[string][]string{},
+    }
+    for i := 0; i < se.Len(); i++ {
+        row := make([]string, len(fields))
+        for f, field := range fields {
+            row[f] = samm.ValueString(samm.FieldValue(field, i))
+        }
+        snapshot.rows[row[0]] = row
+    }
+    return snapshot
+}
+
+/* diffFrame returns the rows of current that are new or changed since
+ * previous, and the rows of previous that are gone. */
+func diffFrame(previous *streamSnapshot, current *streamSnapshot, now time.Time) *data.Frame {
+    changes := map[string]string{}
+    rows := map[string][]string{}
+    for key, row := range current.rows {
+        old, ok := previous.rows[key]
+        if !ok {
+            changes[key] = "added"
+            rows[key] = row
+            continue
+        }
+        for f := range row {
+            if f >= len(old) || row[f] != old[f] {
+                changes[key] = "changed"
+                rows[key] = row
+                break
+            }
+        }
+    }
+    for key, row := range previous.rows {
+        if _, ok := current.rows[key]; !ok {
+            changes[key] = "removed"
+            rows[key] = row
+        }
+    }
+    return snapshotFrame(current.fields, rows, changes, now)
+}
+
+func snapshotFrame(fieldList []string, rows map[string][]string, changes map[string]string, now time.Time) *data.Frame {
+    keys := make([]string, 0, len(rows))
+    for key := range rows {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+
+    times := make([]time.Time, len(keys))
+    changeValues := make([]string, len(keys))
+    columns := make([][]string, len(fieldList))
+    for f := range fieldList {
+        columns[f] = make([]string, len(keys))
+    }
+    for i, key := range keys {
+        times[i] = now
+        changeValues[i] = changes[key]
+        for f := range fieldList {
+            if f < len(rows[key]) {
+                columns[f][i] = rows[key][f]
+            }
+        }
+    }
+    frame := data.NewFrame("state",
+        data.NewField("Time", nil, times),
+        data.NewField("Change", nil, changeValues),
+    )
+    for f, name := range fieldList {
+        frame.Fields = append(frame.Fields, data.NewField(name, nil, columns[f]))
+    }
+    return frame
+}
+
+/* loadSnapshot queries AWS, the result fills the cache when it is not
+ * valid. */
+func (d *Datasource) loadSnapshot(path string) (*streamSnapshot, error) {
+    switch path {
+    case "workspaces/state":
+        sw := samm.NewSammWorkspace(workspaces.New(d.AwsSession), nil, 0)
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        d.Cache.Get("workspaces.Workspace").Update(&sw, err)
+        if err != nil {
+            return nil, err
+        }
+        return newStreamSnapshot(sw, "WorkspaceId"), nil
+
+    case "appstream/sessions":
+        svc := appstream.New(d.AwsSession)
+        sf := samm.NewSammFleet(svc, nil, 0)
+        cacheItem := d.Cache.Get("appstream.Fleet")
+        err := sf.UpdateElements(cacheItem.Load())
+        cacheItem.Update(&sf, err)
+        if err != nil {
+            return nil, err
+        }
+        /* DescribeSessions needs the stack and the fleet */
+        sessions := []interface{}{}
+        for _, e := range sf.Elements() {
+            fleetName := aws.StringValue(e.(*appstream.Fleet).Name)
+            fleetCondition := models.FilterCondition{Property: "FleetName", Value: fleetName}
+            sa := samm.NewSammAssociatedStacks(svc, []models.FilterCondition{fleetCondition}, 0)
+            if err := sa.UpdateElements([]interface{}{}, nil, false); err != nil {
+                return nil, err
+            }
+            for _, stack := range sa.Elements() {
+                stackCondition := models.FilterCondition{Property: "StackName", Value: aws.StringValue(stack.(*string))}
+                ss := samm.NewSammSession(svc, []models.FilterCondition{fleetCondition, stackCondition}, 0)
+                if err := ss.UpdateElements([]interface{}{}, nil, false); err != nil {
+                    return nil, err
+                }
+                sessions = append(sessions, ss.Elements()...)
+            }
+        }
+        ss := samm.NewSammSession(svc, nil, 0)
+        ss.UpdateElements(sessions, nil, true)
+        return newStreamSnapshot(ss, "Id"), nil
+    }
+    return nil, nil
+}
+
+func (d *Datasource) SubscribeStream(_ context.Context, req *backend.SubscribeStreamRequest) (*backend.SubscribeStreamResponse, error) {
+    if req.Path != "workspaces/state" && req.Path != "appstream/sessions" {
+        return &backend.SubscribeStreamResponse{
+            Status: backend.SubscribeStreamStatusNotFound,
+        }, nil
+    }
+    response := &backend.SubscribeStreamResponse{
+        Status: backend.SubscribeStreamStatusOK,
+    }
+    /* The shared snapshot is the baseline of the changes sent by RunStream,
+     * a stale one is reloaded for the initial data only. */
+    snapshot := d.streams.get(req.Path)
+    if snapshot == nil || time.Since(snapshot.time) > d.streamInterval {
+        var err error
+        snapshot, err = d.loadSnapshot(req.Path)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to load the initial snapshot", "path", req.Path, "error", err.Error())
+            return response, nil
+        }
+    }
+    changes := map[string]string{}
+    for key := range snapshot.rows {
+        changes[key] = "current"
+    }
+    initialData, err := backend.NewInitialFrame(snapshotFrame(snapshot.fields, snapshot.rows, changes, time.Now()), data.IncludeAll)
+    if err != nil {
+        return nil, err
+    }
+    response.InitialData = initialData
+    return response, nil
+}
+
+func (d *Datasource) PublishStream(_ context.Context, _ *backend.PublishStreamRequest) (*backend.PublishStreamResponse, error) {
+    return &backend.PublishStreamResponse{
+        Status: backend.PublishStreamStatusPermissionDenied,
+    }, nil
+}
+
+func (d *Datasource) RunStream(ctx context.Context, req *backend.RunStreamRequest, sender *backend.StreamSender) error {
+    log.DefaultLogger.Info("Stream started", "path", req.Path, "interval", d.streamInterval.String())
+    ticker := time.NewTicker(d.streamInterval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            log.DefaultLogger.Info("Stream stopped", "path", req.Path)
+            return nil
+        case <-ticker.C:
+            current, err := d.loadSnapshot(req.Path)
+            if err != nil {
+                log.DefaultLogger.Warn("Unable to poll the stream state", "path", req.Path, "error", err.Error())
+                continue
+            }
+            previous := d.streams.get(req.Path)
+            d.streams.set(req.Path, current)
+            if previous == nil {
+                previous = &streamSnapshot{rows: map[string][]string{}}
+            }
+            frame := diffFrame(previous, current, time.Now())
+            if frame.Rows() == 0 {
+                continue
+            }
+            if err = sender.SendFrame(frame, data.IncludeAll); err != nil {
+                log.DefaultLogger.Error("Unable to send the stream changes", "path", req.Path, "error", err.Error())
+                return err
+            }
+        }
+    }
+}
@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/samana-group/sammaws/pkg/cache"
+	"github.com/samana-group/sammaws/pkg/samm"
+)
+
+func snapshotColumn(snapshot *streamSnapshot, name string) map[string]string {
+	values := map[string]string{}
+	for f, fieldName := range snapshot.fields {
+		if fieldName != name {
+			continue
+		}
+		for key, row := range snapshot.rows {
+			values[key] = row[f]
+		}
+	}
+	return values
+}
+
+func TestNewStreamSnapshot(t *testing.T) {
+	snapshot := newStreamSnapshot(testWorkspaces("AVAILABLE", "STOPPED"), "WorkspaceId")
+	if snapshot.fields[0] != "WorkspaceId" {
+		t.Fatalf("the key must be the first field, got %v", snapshot.fields)
+	}
+	want := map[string]string{"ws-0": "AVAILABLE", "ws-1": "STOPPED"}
+	if got := snapshotColumn(snapshot, "State"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected states %v", got)
+	}
+	if time.Since(snapshot.time) > time.Minute {
+		t.Fatal("the snapshot must keep the time it is taken")
+	}
+}
+
+func TestDiffFrame(t *testing.T) {
+	previous := newStreamSnapshot(testWorkspaces("AVAILABLE", "STOPPED", "AVAILABLE"), "WorkspaceId")
+	current := newStreamSnapshot(testWorkspaces("STOPPED", "STOPPED"), "WorkspaceId")
+	current.rows["ws-3"] = []string{"ws-3", "AVAILABLE"}
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	frame := diffFrame(previous, current, now)
+	if got := frameColumn(t, frame, "WorkspaceId"); !reflect.DeepEqual(got, []interface{}{"ws-0", "ws-2", "ws-3"}) {
+		t.Fatalf("unchanged rows must not be sent, got %v", got)
+	}
+	if got := frameColumn(t, frame, "Change"); !reflect.DeepEqual(got, []interface{}{"changed", "removed", "added"}) {
+		t.Fatalf("unexpected changes %v", got)
+	}
+	if got := frameColumn(t, frame, "Time"); got[0] != now {
+		t.Fatalf("unexpected time %v", got[0])
+	}
+
+	if frame = diffFrame(current, current, now); frame.Rows() != 0 {
+		t.Fatalf("the same snapshot must give no change, got %d rows", frame.Rows())
+	}
+}
+
+func TestSubscribeStreamInitialData(t *testing.T) {
+	snapshot := newStreamSnapshot(testWorkspaces("AVAILABLE"), "WorkspaceId")
+	ds := &Datasource{
+		streams:        &streamState{snapshots: map[string]*streamSnapshot{"workspaces/state": snapshot}},
+		streamInterval: time.Minute,
+	}
+	/* AwsSession is nil: a reload of the snapshot would panic */
+	response, err := ds.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "workspaces/state"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != backend.SubscribeStreamStatusOK || response.InitialData == nil {
+		t.Fatalf("a fresh snapshot must be sent as initial data, got %+v", response)
+	}
+}
+
+func TestSubscribeStreamKeepsBaseline(t *testing.T) {
+	stale := newStreamSnapshot(testWorkspaces("AVAILABLE"), "WorkspaceId")
+	stale.time = time.Now().Add(-time.Hour)
+	ds := &Datasource{
+		AwsSession: session.Must(session.NewSession(&aws.Config{
+			Region:      aws.String("us-east-1"),
+			Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		})),
+		Cache:          cache.NewCacheMap(time.Hour),
+		streams:        &streamState{snapshots: map[string]*streamSnapshot{"appstream/sessions": stale}},
+		streamInterval: time.Minute,
+	}
+	/* no fleet in the cache: the reload makes no AWS call */
+	sf := samm.NewSammFleet(nil, nil, 0)
+	sf.UpdateElements([]interface{}{}, nil, true)
+	ds.Cache.Get("appstream.Fleet").Update(&sf, nil)
+
+	response, err := ds.SubscribeStream(context.Background(), &backend.SubscribeStreamRequest{Path: "appstream/sessions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Status != backend.SubscribeStreamStatusOK || response.InitialData == nil {
+		t.Fatalf("a reloaded snapshot must be sent as initial data, got %+v", response)
+	}
+	if ds.streams.get("appstream/sessions") != stale {
+		t.Fatal("SubscribeStream must not replace the baseline of RunStream")
+	}
+}
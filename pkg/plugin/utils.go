@@ -11,6 +11,7 @@ import (
     "github.com/grafana/grafana-plugin-sdk-go/data"
     "github.com/grafana/grafana-plugin-sdk-go/backend/log"
 
+    "github.com/samana-group/sammaws/pkg/models"
     "github.com/samana-group/sammaws/pkg/samm"
 )
 
@@ -46,17 +47,46 @@ func fieldsToResponse(fields []string, fieldlist []string) backend.DataResponse
     return backend.DataResponse{Frames: []*data.Frame{frame}}
 }
 
-func CreateFrame(elements samm.SammElement, fieldList []string, Name string) (*data.Frame, error) {
+func CreateFrame(elements samm.SammElement, queryData models.QueryModel, Name string) (*data.Frame, error) {
     frame := data.NewFrame(Name)
     frame.Meta = &data.FrameMeta{
         PreferredVisualization: "table",
     }
 
+    fieldList := queryData.FieldList
     if len(fieldList) == 0 {
         fieldList = elements.DefaultFieldList()
     }
 
+    rows, err := samm.FilterElements(elements, elements.ClientFilterConditions())
+    if err != nil {
+        return nil, err
+    }
+    if queryData.IsAggregation() {
+        return aggregateFrame(elements, rows, queryData, Name)
+    }
+    err = samm.SortElements(elements, rows, queryData.SortBy)
+    if err != nil {
+        return nil, err
+    }
+    rows = samm.PageRows(rows, queryData.Offset, queryData.RowLimit)
+
+    var generic []interface{}
     for _, fieldName := range fieldList {
+        if isComputedField(fieldName) {
+            computed, err := parseComputedField(fieldName)
+            if err != nil {
+                return nil, err
+            }
+            if generic == nil {
+                generic, err = genericRows(elements, rows)
+                if err != nil {
+                    return nil, err
+                }
+            }
+            frame.Fields = append(frame.Fields, computed.toField(computed.evaluate(generic)))
+            continue
+        }
         fieldType, ok := elements.AttributeType(fieldName)
         if ! ok {
             return nil, fmt.Errorf("Invalid field %s.", fieldName)
@@ -65,14 +95,12 @@ func CreateFrame(elements samm.SammElement, fieldList []string, Name string) (*d
         if f == nil {
             return nil, fmt.Errorf("Unable to create field for type %s.", fieldName)
         }
-        frame.Fields = append(frame.Fields, f)
-    }
-    for itemIndex := 0; itemIndex < elements.Len(); itemIndex++ {
-        for index, fieldName := range fieldList {
-            elements.AppendData(itemIndex, frame.Fields[index], fieldName)
+        for _, itemIndex := range rows {
+            elements.AppendData(itemIndex, f, fieldName)
         }
+        frame.Fields = append(frame.Fields, f)
     }
-    log.DefaultLogger.Debug("Frame created.", "elementsInFrame", elements.Len())
+    log.DefaultLogger.Debug("Frame created.", "elements", elements.Len(), "elementsInFrame", len(rows))
     return frame, nil
 }
 
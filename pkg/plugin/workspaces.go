@@ -3,10 +3,15 @@ package plugin
 import (
     "fmt"
     "errors"
+    "strconv"
+    "strings"
+    "time"
     "encoding/json"
+    "github.com/aws/aws-sdk-go/aws"
     "github.com/aws/aws-sdk-go/service/workspaces"
 
     "github.com/grafana/grafana-plugin-sdk-go/backend"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
 
     "github.com/samana-group/sammaws/pkg/models"
     "github.com/samana-group/sammaws/pkg/samm"
@@ -39,6 +44,16 @@ func (w WorkspacesQuery) QueryData() backend.DataResponse {
     case "DescribeWorkspacesFields":
         return w.workspaceFieldsToResponse()
     
+    case "DescribeWorkspaces360":
+        return w.workspaces360ToResponse()
+    case "DescribeWorkspaces360Fields":
+        return w.workspaces360FieldsToResponse()
+
+    case "WorkspacesUsageReport":
+        return w.workspacesUsageToResponse()
+    case "WorkspacesUsageReportFields":
+        return w.workspacesUsageFieldsToResponse()
+
     case "DescribeWorkspacesConnectionStatus":
         return w.workspacesConnectionStatusToResponse()
     case "DescribeWorkspacesConnectionStatusFields":
@@ -54,6 +69,56 @@ func (w WorkspacesQuery) QueryData() backend.DataResponse {
     case "DescribeWorkspaceBundlesFields":
         return w.workspaceBundlesFieldsToResponse()
     
+    case "DescribeIpGroups":
+        return w.ipGroupsToResponse()
+    case "DescribeIpGroupsFields":
+        return w.ipGroupsFieldsToResponse()
+    
+    case "DescribeWorkspaceSnapshots":
+        return w.workspaceSnapshotsToResponse()
+    case "DescribeWorkspaceSnapshotsFields":
+        return w.workspaceSnapshotsFieldsToResponse()
+    
+    case "DescribeWorkspaceImages":
+        return w.workspaceImagesToResponse()
+    case "DescribeWorkspaceImagesFields":
+        return w.workspaceImagesFieldsToResponse()
+    
+    case "DescribeWorkspaceImagePermissions":
+        return w.workspaceImagePermissionsToResponse()
+    case "DescribeWorkspaceImagePermissionsFields":
+        return w.workspaceImagePermissionsFieldsToResponse()
+    
+    case "DescribeWorkspacesPools":
+        return w.workspacesPoolsToResponse()
+    case "DescribeWorkspacesPoolsFields":
+        return w.workspacesPoolsFieldsToResponse()
+    
+    case "DescribeWorkspacesPoolSessions":
+        return w.workspacesPoolSessionsToResponse()
+    case "DescribeWorkspacesPoolSessionsFields":
+        return w.workspacesPoolSessionsFieldsToResponse()
+    
+    case "DescribeAccount":
+        return w.accountToResponse()
+    case "DescribeAccountFields":
+        return w.accountFieldsToResponse()
+    
+    case "DescribeAccountModifications":
+        return w.accountModificationsToResponse()
+    case "DescribeAccountModificationsFields":
+        return w.accountModificationsFieldsToResponse()
+    
+    case "DescribeConnectionAliases":
+        return w.connectionAliasesToResponse()
+    case "DescribeConnectionAliasesFields":
+        return w.connectionAliasesFieldsToResponse()
+    
+    case "DescribeConnectionAliasPermissions":
+        return w.connectionAliasPermissionsToResponse()
+    case "DescribeConnectionAliasPermissionsFields":
+        return w.connectionAliasPermissionsFieldsToResponse()
+    
     case "Echo":
         return w.echoToResponse()
     }
@@ -84,6 +149,24 @@ func (w WorkspacesQuery) CallAction() ([]byte, error) {
     case "restore-workspace":
         return w.restoreWorkspace()
     
+    case "authorize-ip-rules":
+        return w.authorizeIpRules()
+    
+    case "revoke-ip-rules":
+        return w.revokeIpRules()
+    
+    case "start-workspaces-pool":
+        return w.startWorkspacesPool()
+    
+    case "stop-workspaces-pool":
+        return w.stopWorkspacesPool()
+    
+    case "update-workspaces-pool":
+        return w.updateWorkspacesPool()
+    
+    case "terminate-workspaces-pool-session":
+        return w.terminateWorkspacesPoolSession()
+    
     case "echo":
         return w.echo()
     
@@ -121,6 +204,42 @@ func (w WorkspacesQuery) ListActions() ([]byte, error) {
             Disabled: disabled,
             Confirm: true,
         },
+        {
+            Action: "authorize-ip-rules",
+            DisplayName: "Authorize IP Rules",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "revoke-ip-rules",
+            DisplayName: "Revoke IP Rules",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "start-workspaces-pool",
+            DisplayName: "Start Pool",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "stop-workspaces-pool",
+            DisplayName: "Stop Pool",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "update-workspaces-pool",
+            DisplayName: "Update Pool",
+            Disabled: disabled,
+            Confirm: true,
+        },
+        {
+            Action: "terminate-workspaces-pool-session",
+            DisplayName: "Terminate Pool Session",
+            Disabled: disabled,
+            Confirm: true,
+        },
         {
             Action: "echo",
             DisplayName: "Echo",
@@ -143,6 +262,10 @@ func (w WorkspacesQuery) toVariables(fl backend.DataResponse) ([]byte, error) {
 /*    Queries    */
 func (w WorkspacesQuery) workspaceFieldsToResponse() backend.DataResponse {
     fieldlist := []string{ "Label", "Value" }
+    return fieldsToResponse(w.workspaceFields(), fieldlist)
+}
+
+func (w WorkspacesQuery) workspaceFields() []string {
     fields := []string{
         "BundleId",
         "ComputerName",
@@ -164,7 +287,12 @@ func (w WorkspacesQuery) workspaceFieldsToResponse() backend.DataResponse {
         "WorkspaceName",
         "WorkspaceProperties",
     }
-    return fieldsToResponse(fields, fieldlist)
+    if w.dataSource.Pricing != nil {
+        fields = append(fields, "EstimatedMonthlyCost", "HourlyRate", "MonthlyRate")
+    }
+    fields = append(fields, w.workspaceTagFields()...)
+    fields = append(fields, samm.NewSammWorkspace(w.svc, nil, 0).NestedFieldList()...)
+    return fields
 }
 
 func (w WorkspacesQuery) workspacesToResponse() backend.DataResponse {
@@ -180,7 +308,7 @@ func (w WorkspacesQuery) workspacesToResponse() backend.DataResponse {
         }
     } else {
         cacheItem := w.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -188,7 +316,12 @@ func (w WorkspacesQuery) workspacesToResponse() backend.DataResponse {
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, w.queryData.FieldList, w.refID)
+    if samm.NeedsTags(w.queryData.ReferencedFields(), w.queryData.FilterConditions) {
+        sw.SetTags(w.resourceTags(sw.ResourceIds(), -1))
+    }
+    sw.SetPricing(w.dataSource.Pricing, aws.StringValue(w.dataSource.AwsSession.Config.Region))
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
     if err != nil {
         response.Error = err
         return response
@@ -201,6 +334,171 @@ func (w WorkspacesQuery) workspacesToResponse() backend.DataResponse {
 /* ************************************************************* */
 
 
+func (w WorkspacesQuery) workspaces360FieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "BundleName",
+        "ComputeType",
+        "ConnectionState",
+        "ConnectionStateCheckTimestamp",
+        "DirectoryAlias",
+        "DirectoryName",
+        "DirectoryState",
+        "DirectoryType",
+        "LastKnownUserConnectionTimestamp",
+        "RootStorageCapacity",
+        "UserStorageCapacity",
+    }
+    fields = append(fields, w.workspaceFields()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) workspacesUsageFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "DaysSinceLastConnection",
+        "RunningMode",
+        "UsageStatus",
+    }
+    response := w.workspaces360FieldsToResponse()
+    frame := response.Frames[0]
+    for i := 0; i < frame.Fields[0].Len(); i++ {
+        fields = append(fields, frame.Fields[0].At(i).(string))
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+/* The usage report flags the idle, never used and rarely used AlwaysOn
+ * workspaces, the thresholds being idleDays and rarelyUsedDays. */
+func (w WorkspacesQuery) workspacesUsageToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    s360 := w.workspaces360(&response)
+    usage := samm.NewSammWorkspaceUsage(s360, w.queryData.IdleDays, w.queryData.RarelyUsedDays, time.Now())
+    frame, err := CreateFrame(usage, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+
+/* The workspaces are joined with the cached connection status, bundles and
+ * directories. Bundles not in the cache, like the Amazon owned ones, are
+ * looked up by id. */
+func (w WorkspacesQuery) workspaces360(response *backend.DataResponse) samm.SammWorkspace360 {
+    sw := samm.NewSammWorkspace(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "workspaces.Workspace"
+    if len(w.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := w.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    if samm.NeedsTags(w.queryData.ReferencedFields(), w.queryData.FilterConditions) {
+        sw.SetTags(w.resourceTags(sw.ResourceIds(), -1))
+    }
+    sw.SetPricing(w.dataSource.Pricing, aws.StringValue(w.dataSource.AwsSession.Config.Region))
+
+    sc := samm.NewSammWorkspacesConnectionStatus(w.svc, []models.FilterCondition{}, w.queryData.Limit)
+    connectionStatus, err := w.cachedElements("workspaces.WorkspaceConnectionStatus", &sc)
+    if err != nil {
+        response.Error = err
+    }
+    sb := samm.NewSammWorkspaceBundle(w.svc, []models.FilterCondition{}, w.queryData.Limit)
+    bundles, err := w.cachedElements("workspaces.WorkspaceBundle", &sb)
+    if err != nil {
+        response.Error = err
+    }
+    bundles = append(bundles, w.missingBundles(sw, bundles)...)
+    sd := samm.NewSammWorkspacesDirectory(w.svc, []models.FilterCondition{}, w.queryData.Limit)
+    directories, err := w.cachedElements("workspaces.WorkspacesDirectory", &sd)
+    if err != nil {
+        response.Error = err
+    }
+
+    return samm.NewSammWorkspace360(sw, connectionStatus, bundles, directories)
+}
+
+func (w WorkspacesQuery) workspaces360ToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    s360 := w.workspaces360(&response)
+    frame, err := CreateFrame(s360, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+
+type updatableElement interface {
+    samm.SammElement
+    UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error)
+}
+
+func (w WorkspacesQuery) cachedElements(serviceKey string, se updatableElement) ([]interface{}, error) {
+    cacheItem := w.dataSource.Cache.Get(serviceKey)
+    err := se.UpdateElements(cacheItem.Load())
+    cacheItem.Update(se, err)
+    return se.Elements(), err
+}
+
+/* missingBundles returns the bundles of the workspaces the account does
+ * not own, the AMAZON bundles for example. They are cached by bundle id. */
+func (w WorkspacesQuery) missingBundles(sw samm.SammWorkspace, bundles []interface{}) []interface{} {
+    known := map[string]bool{}
+    for _, e := range bundles {
+        known[aws.StringValue(e.(*workspaces.WorkspaceBundle).BundleId)] = true
+    }
+    elements := []interface{}{}
+    filterConditions := []models.FilterCondition{}
+    for _, e := range sw.Elements() {
+        bundleId := aws.StringValue(e.(*workspaces.Workspace).BundleId)
+        if bundleId == "" || known[bundleId] {
+            continue
+        }
+        known[bundleId] = true
+        cached, _, valid := w.dataSource.Cache.Get("workspaces.WorkspaceBundle." + bundleId).Load()
+        if valid {
+            elements = append(elements, cached...)
+            continue
+        }
+        filterConditions = append(filterConditions, models.FilterCondition{Property: "BundleId", Value: bundleId})
+    }
+    /* DescribeWorkspaceBundles accepts up to 25 bundle ids */
+    for start := 0; start < len(filterConditions); start += 25 {
+        end := min(start + 25, len(filterConditions))
+        sb := samm.NewSammWorkspaceBundle(w.svc, filterConditions[start:end], 0)
+        err := sb.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to describe bundles", "error", err.Error())
+        }
+        for _, e := range sb.Elements() {
+            bundle := samm.NewSammWorkspaceBundle(w.svc, nil, 0)
+            bundle.UpdateElements([]interface{}{e}, nil, true)
+            w.dataSource.Cache.Get("workspaces.WorkspaceBundle." + aws.StringValue(e.(*workspaces.WorkspaceBundle).BundleId)).Update(&bundle, nil)
+        }
+        elements = append(elements, sb.Elements()...)
+    }
+    return elements
+}
+
 func (w WorkspacesQuery) workspacesConnectionStatusFieldsToResponse() backend.DataResponse {
     fieldlist := []string{ "Label", "Value" }
     fields := []string{
@@ -225,7 +523,7 @@ func (w WorkspacesQuery) workspacesConnectionStatusToResponse() backend.DataResp
         }
     } else {
         cacheItem := w.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -233,7 +531,7 @@ func (w WorkspacesQuery) workspacesConnectionStatusToResponse() backend.DataResp
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, w.queryData.FieldList, w.refID)
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
     if err != nil {
         response.Error = err
         return response
@@ -275,6 +573,8 @@ func (w WorkspacesQuery) workspaceDirectoriesFieldsToResponse() backend.DataResp
         "WorkspaceSecurityGroupId",
         "WorkspaceType",
     }
+    fields = append(fields, w.workspaceDirectoryTagFields()...)
+    fields = append(fields, samm.NewSammWorkspacesDirectory(w.svc, nil, 0).NestedFieldList()...)
     return fieldsToResponse(fields, fieldlist)
 }
 
@@ -291,7 +591,7 @@ func (w WorkspacesQuery) workspaceDirectoriesToResponse() backend.DataResponse {
         }
     } else {
         cacheItem := w.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -299,7 +599,11 @@ func (w WorkspacesQuery) workspaceDirectoriesToResponse() backend.DataResponse {
     }
     /* End Process Cache */
 
-    frame, err := CreateFrame(sw, w.queryData.FieldList, w.refID)
+    if samm.NeedsTags(w.queryData.ReferencedFields(), w.queryData.FilterConditions) {
+        sw.SetTags(w.resourceTags(sw.ResourceIds(), -1))
+    }
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
     if err != nil {
         response.Error = err
         return response
@@ -327,6 +631,7 @@ func (w WorkspacesQuery) workspaceBundlesFieldsToResponse() backend.DataResponse
         "State",
         "UserStorage",
     }
+    fields = append(fields, samm.NewSammWorkspaceBundle(w.svc, nil, 0).NestedFieldList()...)
     return fieldsToResponse(fields, fieldlist)
 }
 
@@ -343,7 +648,7 @@ func (w WorkspacesQuery) workspaceBundlesToResponse() backend.DataResponse {
         }
     } else {
         cacheItem := w.dataSource.Cache.Get(serviceKey)
-        err := sw.UpdateElements(cacheItem.Objects.([]interface{}), cacheItem.NextToken, cacheItem.IsValid())
+        err := sw.UpdateElements(cacheItem.Load())
         if err != nil {
             response.Error = err
         }
@@ -352,7 +657,7 @@ func (w WorkspacesQuery) workspaceBundlesToResponse() backend.DataResponse {
     /* End Process Cache */
 
 
-    frame, err := CreateFrame(sw, w.queryData.FieldList, w.refID)
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
     if err != nil {
         response.Error = err
         return response
@@ -363,77 +668,689 @@ func (w WorkspacesQuery) workspaceBundlesToResponse() backend.DataResponse {
     return response
 }
 /* ************************************************************* */
-func (w WorkspacesQuery) echoToResponse() backend.DataResponse {
+
+func (w WorkspacesQuery) ipGroupsFieldsToResponse() backend.DataResponse {
     fieldlist := []string{ "Label", "Value" }
     fields := []string{
-        "echo1",
-        "echo2",
-        "echo3",
-        "echo4",
-        "echo5",
-        "echo6",
-        "echo7",
-        "echo8",
-        "echo9",
-        "echo0",
+        "DirectoryIds",
+        "GroupDesc",
+        "GroupId",
+        "GroupName",
+        "IpRule",
+        "RuleDesc",
     }
     return fieldsToResponse(fields, fieldlist)
 }
 
-/*    Actions    */
-func (w WorkspacesQuery) startWorkspaces() ([]byte, error) {
-    sr := workspaces.StartRequest {
-        WorkspaceId: &w.actionData.Id,
-    }
-    input := workspaces.StartWorkspacesInput{
-        StartWorkspaceRequests: []*workspaces.StartRequest{&sr},
+func (w WorkspacesQuery) ipGroupsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammIpGroup(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "workspaces.IpGroup"
+    if len(w.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := w.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
     }
-    _, err := w.svc.StartWorkspaces(&input)
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
     if err != nil {
-        return []byte{}, err
+        response.Error = err
+        return response
     }
-    return []byte("{ \"message\": \"Workspace is being Started\" }"), nil
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
 }
+/* ************************************************************* */
 
-func (w WorkspacesQuery) stopWorkspaces() ([]byte, error) {
-    sr := workspaces.StopRequest {
-        WorkspaceId: &w.actionData.Id,
+func (w WorkspacesQuery) workspaceSnapshotsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "SnapshotTime",
+        "SnapshotType",
+        "WorkspaceId",
     }
-    input := workspaces.StopWorkspacesInput{
-        StopWorkspaceRequests: []*workspaces.StopRequest{&sr},
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) workspaceSnapshotsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammWorkspaceSnapshot(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    err := sw.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
     }
-    _, err := w.svc.StopWorkspaces(&input)
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
     if err != nil {
-        return []byte{}, err
+        response.Error = err
+        return response
     }
-    return []byte("{ \"message\": \"Workspace is being Stopped\" }"), nil
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
 }
+/* ************************************************************* */
 
-func (w WorkspacesQuery) rebootWorkspaces() ([]byte, error) {
-    sr := workspaces.RebootRequest {
-        WorkspaceId: &w.actionData.Id,
+func (w WorkspacesQuery) workspaceImagesFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "Created",
+        "Description",
+        "ErrorCode",
+        "ErrorDetails",
+        "ErrorMessage",
+        "ImageId",
+        "Name",
+        "OperatingSystem",
+        "OwnerAccountId",
+        "RequiredTenancy",
+        "State",
+        "Updates",
     }
-    input := workspaces.RebootWorkspacesInput{
-        RebootWorkspaceRequests: []*workspaces.RebootRequest{&sr},
+    fields = append(fields, samm.NewSammWorkspaceImage(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) workspaceImagesToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammWorkspaceImage(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "workspaces.WorkspaceImage"
+    if len(w.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := w.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
     }
-    _, err := w.svc.RebootWorkspaces(&input)
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
     if err != nil {
-        return []byte{}, err
+        response.Error = err
+        return response
     }
-    return []byte("{ \"message\": \"Workspace is being Rebooted\" }"), nil
-}
 
-func (w WorkspacesQuery) restoreWorkspace() ([]byte, error) {
-    return []byte{}, errors.New("Not Implemented restore")
-}
+    response.Frames = append(response.Frames, frame)
 
-func (w WorkspacesQuery) echo() ([]byte, error) {
-    return []byte(fmt.Sprintf("{ \"message\": \"You requested an echo from: %s\" }", w.actionData.Id)), nil
+    return response
 }
+/* ************************************************************* */
 
-func (w WorkspacesQuery) listActions() ([]byte, error) {
+func (w WorkspacesQuery) workspaceImagePermissionsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "ImageId",
+        "SharedAccountId",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) workspaceImagePermissionsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammWorkspaceImagePermission(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    err := sw.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
+    }
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w WorkspacesQuery) workspacesPoolsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "ApplicationSettings",
+        "BundleId",
+        "CapacityStatus.ActiveUserSessions",
+        "CapacityStatus.ActualUserSessions",
+        "CapacityStatus.AvailableUserSessions",
+        "CapacityStatus.DesiredUserSessions",
+        "CreatedAt",
+        "Description",
+        "DirectoryId",
+        "Errors",
+        "PoolArn",
+        "PoolId",
+        "PoolName",
+        "State",
+        "TimeoutSettings.DisconnectTimeoutInSeconds",
+        "TimeoutSettings.IdleDisconnectTimeoutInSeconds",
+        "TimeoutSettings.MaxUserDurationInSeconds",
+    }
+    fields = append(fields, samm.NewSammWorkspacesPool(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) workspacesPoolsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammWorkspacesPool(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "workspaces.WorkspacesPool"
+    if len(w.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := w.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w WorkspacesQuery) workspacesPoolSessionsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AuthenticationType",
+        "ConnectionState",
+        "ExpirationTime",
+        "InstanceId",
+        "NetworkAccessConfiguration.EniId",
+        "NetworkAccessConfiguration.EniPrivateIpAddress",
+        "PoolId",
+        "SessionId",
+        "StartTime",
+        "UserId",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) workspacesPoolSessionsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammWorkspacesPoolSession(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    err := sw.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
+    }
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w WorkspacesQuery) accountFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "DedicatedTenancyAccountType",
+        "DedicatedTenancyManagementCidrRange",
+        "DedicatedTenancySupport",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) accountToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammAccount(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "workspaces.Account"
+    if len(w.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := w.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w WorkspacesQuery) accountModificationsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "DedicatedTenancyManagementCidrRange",
+        "DedicatedTenancySupport",
+        "ErrorCode",
+        "ErrorMessage",
+        "ModificationState",
+        "StartTime",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) accountModificationsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammAccountModification(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "workspaces.AccountModification"
+    if len(w.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := w.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w WorkspacesQuery) connectionAliasesFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AliasId",
+        "Associations",
+        "ConnectionString",
+        "OwnerAccountId",
+        "State",
+    }
+    fields = append(fields, samm.NewSammConnectionAlias(w.svc, nil, 0).NestedFieldList()...)
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) connectionAliasesToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammConnectionAlias(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    /* Process Cache */
+    serviceKey := "workspaces.ConnectionAlias"
+    if len(w.queryData.FilterConditions) > 0 {
+        err := sw.UpdateElements([]interface{}{}, nil, false)
+        if err != nil {
+            response.Error = err
+        }
+    } else {
+        cacheItem := w.dataSource.Cache.Get(serviceKey)
+        err := sw.UpdateElements(cacheItem.Load())
+        if err != nil {
+            response.Error = err
+        }
+        cacheItem.Update(sw, err)
+    }
+    /* End Process Cache */
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+
+func (w WorkspacesQuery) connectionAliasPermissionsFieldsToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "AliasId",
+        "AllowAssociation",
+        "SharedAccountId",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+func (w WorkspacesQuery) connectionAliasPermissionsToResponse() backend.DataResponse {
+    var response backend.DataResponse
+    sw := samm.NewSammConnectionAliasPermission(w.svc, w.queryData.FilterConditions, w.queryData.Limit)
+
+    err := sw.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        response.Error = err
+    }
+
+    frame, err := CreateFrame(sw, w.queryData, w.refID)
+    if err != nil {
+        response.Error = err
+        return response
+    }
+
+    response.Frames = append(response.Frames, frame)
+
+    return response
+}
+/* ************************************************************* */
+func (w WorkspacesQuery) echoToResponse() backend.DataResponse {
+    fieldlist := []string{ "Label", "Value" }
+    fields := []string{
+        "echo1",
+        "echo2",
+        "echo3",
+        "echo4",
+        "echo5",
+        "echo6",
+        "echo7",
+        "echo8",
+        "echo9",
+        "echo0",
+    }
+    return fieldsToResponse(fields, fieldlist)
+}
+
+/*    Tags    */
+
+/* The field lists only discover the tag keys already cached, and those of
+ * up to maxTagDiscoveryCalls more resources, each needing a DescribeTags. */
+const maxTagDiscoveryCalls = 20
+
+/* resourceTags returns the tags of the resources, with at most maxCalls
+ * DescribeTags calls for the tags not cached, -1 for no limit. */
+func (w WorkspacesQuery) resourceTags(resourceIds []string, maxCalls int) samm.ResourceTags {
+    tags := samm.ResourceTags{}
+    calls := 0
+    for _, resourceId := range resourceIds {
+        st := samm.NewSammTag(w.svc, []models.FilterCondition{{Property: "ResourceId", Value: resourceId}}, -1)
+        cacheItem := w.dataSource.Cache.Get("workspaces.Tag." + resourceId)
+        if maxCalls >= 0 && !cacheItem.IsValid() {
+            if calls >= maxCalls {
+                continue
+            }
+            calls++
+        }
+        err := st.UpdateElements(cacheItem.Load())
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to get tags.", "error", err.Error(), "resourceId", resourceId)
+            continue
+        }
+        cacheItem.Update(st, err)
+        tags[resourceId] = st.Map()
+    }
+    return tags
+}
+
+func (w WorkspacesQuery) workspaceTagFields() []string {
+    sw := samm.NewSammWorkspace(w.svc, []models.FilterCondition{}, w.queryData.Limit)
+    cacheItem := w.dataSource.Cache.Get("workspaces.Workspace")
+    err := sw.UpdateElements(cacheItem.Load())
+    cacheItem.Update(sw, err)
+    return w.resourceTags(sw.ResourceIds(), maxTagDiscoveryCalls).Keys()
+}
+
+func (w WorkspacesQuery) workspaceDirectoryTagFields() []string {
+    sw := samm.NewSammWorkspacesDirectory(w.svc, []models.FilterCondition{}, w.queryData.Limit)
+    cacheItem := w.dataSource.Cache.Get("workspaces.WorkspacesDirectory")
+    err := sw.UpdateElements(cacheItem.Load())
+    cacheItem.Update(sw, err)
+    return w.resourceTags(sw.ResourceIds(), maxTagDiscoveryCalls).Keys()
+}
+
+/*    Actions    */
+func (w WorkspacesQuery) startWorkspaces() ([]byte, error) {
+    sr := workspaces.StartRequest {
+        WorkspaceId: &w.actionData.Id,
+    }
+    input := workspaces.StartWorkspacesInput{
+        StartWorkspaceRequests: []*workspaces.StartRequest{&sr},
+    }
+    _, err := w.svc.StartWorkspaces(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Workspace is being Started\" }"), nil
+}
+
+func (w WorkspacesQuery) stopWorkspaces() ([]byte, error) {
+    sr := workspaces.StopRequest {
+        WorkspaceId: &w.actionData.Id,
+    }
+    input := workspaces.StopWorkspacesInput{
+        StopWorkspaceRequests: []*workspaces.StopRequest{&sr},
+    }
+    _, err := w.svc.StopWorkspaces(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Workspace is being Stopped\" }"), nil
+}
+
+func (w WorkspacesQuery) rebootWorkspaces() ([]byte, error) {
+    sr := workspaces.RebootRequest {
+        WorkspaceId: &w.actionData.Id,
+    }
+    input := workspaces.RebootWorkspacesInput{
+        RebootWorkspaceRequests: []*workspaces.RebootRequest{&sr},
+    }
+    _, err := w.svc.RebootWorkspaces(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Workspace is being Rebooted\" }"), nil
+}
+
+func (w WorkspacesQuery) restoreWorkspace() ([]byte, error) {
+    return []byte{}, errors.New("Not Implemented restore")
+}
+
+/* Id is the GroupId. IpRule can hold a comma separated list of CIDRs. */
+func (w WorkspacesQuery) ipRules() ([]string, error) {
+    if w.actionData.Id == "" {
+        return nil, errors.New("GroupId is mandatory")
+    }
+    rules := []string{}
+    for _, rule := range strings.Split(w.actionData.Parameters["IpRule"], ",") {
+        rule = strings.TrimSpace(rule)
+        if rule != "" {
+            rules = append(rules, rule)
+        }
+    }
+    if len(rules) == 0 {
+        return nil, errors.New("Parameter IpRule is mandatory")
+    }
+    return rules, nil
+}
+
+func (w WorkspacesQuery) authorizeIpRules() ([]byte, error) {
+    rules, err := w.ipRules()
+    if err != nil {
+        return []byte{}, err
+    }
+    ruleDesc := w.actionData.Parameters["RuleDesc"]
+    userRules := make([]*workspaces.IpRuleItem, len(rules))
+    for i, rule := range rules {
+        userRules[i] = &workspaces.IpRuleItem{ IpRule: aws.String(rule) }
+        if ruleDesc != "" {
+            userRules[i].SetRuleDesc(ruleDesc)
+        }
+    }
+    input := workspaces.AuthorizeIpRulesInput{
+        GroupId: &w.actionData.Id,
+        UserRules: userRules,
+    }
+    _, err = w.svc.AuthorizeIpRules(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    w.dataSource.Cache.Get("workspaces.IpGroup").Flush()
+    return []byte("{ \"message\": \"IP Rules have been Authorized\" }"), nil
+}
+
+func (w WorkspacesQuery) revokeIpRules() ([]byte, error) {
+    rules, err := w.ipRules()
+    if err != nil {
+        return []byte{}, err
+    }
+    input := workspaces.RevokeIpRulesInput{
+        GroupId: &w.actionData.Id,
+        UserRules: aws.StringSlice(rules),
+    }
+    _, err = w.svc.RevokeIpRules(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    w.dataSource.Cache.Get("workspaces.IpGroup").Flush()
+    return []byte("{ \"message\": \"IP Rules have been Revoked\" }"), nil
+}
+
+func (w WorkspacesQuery) startWorkspacesPool() ([]byte, error) {
+    input := workspaces.StartWorkspacesPoolInput{
+        PoolId: &w.actionData.Id,
+    }
+    _, err := w.svc.StartWorkspacesPool(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Pool is being Started\" }"), nil
+}
+
+func (w WorkspacesQuery) stopWorkspacesPool() ([]byte, error) {
+    input := workspaces.StopWorkspacesPoolInput{
+        PoolId: &w.actionData.Id,
+    }
+    _, err := w.svc.StopWorkspacesPool(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Pool is being Stopped\" }"), nil
+}
+
+func (w WorkspacesQuery) updateWorkspacesPool() ([]byte, error) {
+    if len(w.actionData.Parameters) == 0 {
+        return []byte{}, errors.New("No parameters to update")
+    }
+    input := workspaces.UpdateWorkspacesPoolInput{
+        PoolId: &w.actionData.Id,
+    }
+    timeoutSettings := workspaces.TimeoutSettings{}
+    for parameter, value := range w.actionData.Parameters {
+        switch parameter {
+        case "BundleId":
+            input.SetBundleId(value)
+            continue
+        case "Description":
+            input.SetDescription(value)
+            continue
+        }
+        temp, err := strconv.ParseInt(value, 10, 64)
+        if err != nil {
+            return []byte{}, fmt.Errorf("Invalid value for parameter %s: %s", parameter, value)
+        }
+        switch parameter {
+        case "DesiredUserSessions":
+            input.SetCapacity(&workspaces.Capacity{ DesiredUserSessions: &temp })
+        case "DisconnectTimeoutInSeconds":
+            timeoutSettings.SetDisconnectTimeoutInSeconds(temp)
+            input.SetTimeoutSettings(&timeoutSettings)
+        case "IdleDisconnectTimeoutInSeconds":
+            timeoutSettings.SetIdleDisconnectTimeoutInSeconds(temp)
+            input.SetTimeoutSettings(&timeoutSettings)
+        case "MaxUserDurationInSeconds":
+            timeoutSettings.SetMaxUserDurationInSeconds(temp)
+            input.SetTimeoutSettings(&timeoutSettings)
+        default:
+            return []byte{}, fmt.Errorf("Invalid parameter for update-workspaces-pool: %s", parameter)
+        }
+    }
+    _, err := w.svc.UpdateWorkspacesPool(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Pool is being Updated\" }"), nil
+}
+
+func (w WorkspacesQuery) terminateWorkspacesPoolSession() ([]byte, error) {
+    input := workspaces.TerminateWorkspacesPoolSessionInput{
+        SessionId: &w.actionData.Id,
+    }
+    _, err := w.svc.TerminateWorkspacesPoolSession(&input)
+    if err != nil {
+        return []byte{}, err
+    }
+    return []byte("{ \"message\": \"Pool Session is being Terminated\" }"), nil
+}
+
+func (w WorkspacesQuery) echo() ([]byte, error) {
+    return []byte(fmt.Sprintf("{ \"message\": \"You requested an echo from: %s\" }", w.actionData.Id)), nil
+}
+
+func (w WorkspacesQuery) listActions() ([]byte, error) {
+    switch w.actionData.Resource {
+    case "pool":
+        return w.listWorkspacesPoolActions()
+    case "pool-session":
+        return w.listWorkspacesPoolSessionActions()
+    }
     workspaceId := w.actionData.Id
-    sw := samm.NewSammWorkspace(w.svc, []models.FilterCondition{{"WorkspaceId", workspaceId}} , 1)
+    sw := samm.NewSammWorkspace(w.svc, []models.FilterCondition{{Property: "WorkspaceId", Value: workspaceId}} , 1)
     err := sw.UpdateElements([]interface{}{}, nil, false)
     if err != nil || sw.Len() != 1 {
         return []byte{}, fmt.Errorf("Unable to get information for workspaceId=\"%s\".", workspaceId)
@@ -487,3 +1404,52 @@ func (w WorkspacesQuery) listActions() ([]byte, error) {
     }
     return json.Marshal(actions)
 }
+
+func (w WorkspacesQuery) listWorkspacesPoolActions() ([]byte, error) {
+    poolId := w.actionData.Id
+    sp := samm.NewSammWorkspacesPool(w.svc, []models.FilterCondition{{Property: "PoolId", Value: poolId}}, 1)
+    err := sp.UpdateElements([]interface{}{}, nil, false)
+    if err != nil || sp.Len() != 1 {
+        return []byte{}, fmt.Errorf("Unable to get information for PoolId=\"%s\".", poolId)
+    }
+    pool := sp.At(0).(*workspaces.WorkspacesPool)
+    isAdmin := w.role == "Admin"
+    actions := []SammAwsAction {
+        {
+            Action: "start-workspaces-pool",
+            DisplayName: "Start Pool",
+            Disabled: !(isAdmin && *pool.State == "STOPPED"),
+            Confirm: true,
+        },
+        {
+            Action: "stop-workspaces-pool",
+            DisplayName: "Stop Pool",
+            Disabled: !(isAdmin && (
+                *pool.State == "RUNNING" ||
+                *pool.State == "STARTING")),
+            Confirm: true,
+        },
+        {
+            Action: "update-workspaces-pool",
+            DisplayName: "Update Pool",
+            Disabled: !(isAdmin && (
+                *pool.State == "RUNNING" ||
+                *pool.State == "STOPPED")),
+            Confirm: true,
+        },
+    }
+    return json.Marshal(actions)
+}
+
+func (w WorkspacesQuery) listWorkspacesPoolSessionActions() ([]byte, error) {
+    isAdmin := w.role == "Admin"
+    actions := []SammAwsAction {
+        {
+            Action: "terminate-workspaces-pool-session",
+            DisplayName: "Terminate Pool Session",
+            Disabled: !isAdmin,
+            Confirm: true,
+        },
+    }
+    return json.Marshal(actions)
+}
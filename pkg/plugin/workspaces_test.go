@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+
+	"github.com/samana-group/sammaws/pkg/cache"
+	"github.com/samana-group/sammaws/pkg/samm"
+)
+
+func TestMissingBundlesFromCache(t *testing.T) {
+	ds := &Datasource{Cache: cache.NewCacheMap(time.Hour)}
+	amazonBundle := samm.NewSammWorkspaceBundle(nil, nil, 0)
+	amazonBundle.UpdateElements([]interface{}{&workspaces.WorkspaceBundle{BundleId: aws.String("wsb-amazon")}}, nil, true)
+	ds.Cache.Get("workspaces.WorkspaceBundle.wsb-amazon").Update(&amazonBundle, nil)
+
+	sw := samm.NewSammWorkspace(nil, nil, 0)
+	sw.UpdateElements([]interface{}{
+		&workspaces.Workspace{WorkspaceId: aws.String("ws-1"), BundleId: aws.String("wsb-own")},
+		&workspaces.Workspace{WorkspaceId: aws.String("ws-2"), BundleId: aws.String("wsb-amazon")},
+		&workspaces.Workspace{WorkspaceId: aws.String("ws-3"), BundleId: aws.String("wsb-amazon")},
+	}, nil, true)
+	own := []interface{}{&workspaces.WorkspaceBundle{BundleId: aws.String("wsb-own")}}
+
+	/* svc is nil: any AWS call would panic */
+	w := WorkspacesQuery{dataSource: ds}
+	missing := w.missingBundles(sw, own)
+	if len(missing) != 1 || aws.StringValue(missing[0].(*workspaces.WorkspaceBundle).BundleId) != "wsb-amazon" {
+		t.Fatalf("expected the cached AMAZON bundle only, got %v", missing)
+	}
+}
+
+func TestResourceTagsDiscoveryLimit(t *testing.T) {
+	ds := &Datasource{Cache: cache.NewCacheMap(time.Hour)}
+	st := samm.NewSammTag(nil, nil, -1)
+	st.UpdateElements([]interface{}{&workspaces.Tag{Key: aws.String("Team"), Value: aws.String("blue")}}, nil, true)
+	ds.Cache.Get("workspaces.Tag.ws-1").Update(st, nil)
+
+	/* svc is nil: a DescribeTags call would panic */
+	w := WorkspacesQuery{dataSource: ds}
+	tags := w.resourceTags([]string{"ws-1", "ws-2", "ws-3"}, 0)
+	if len(tags) != 1 || aws.StringValue(tags["ws-1"]["Team"]) != "blue" {
+		t.Fatalf("expected the cached tags only, got %v", tags)
+	}
+}
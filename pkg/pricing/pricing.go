@@ -0,0 +1,153 @@
+package pricing
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+/* A pricing table lists the monthly and hourly rates of the workspaces by
+ * compute type, running mode, operating system name (e.g.
+ * WINDOWS_SERVER_2022) and region. The criteria match the values of the
+ * workspace exactly, case and surrounding spaces aside, an empty or "*"
+ * criterion matches any value. The most specific price is used.
+ *
+ * JSON files hold an array of prices, CSV files have a header line with
+ * the JSON names of the columns:
+ *   computeType,runningMode,operatingSystem,region,monthly,hourly
+ *   STANDARD,ALWAYS_ON,WINDOWS_SERVER_2022,us-east-1,35,
+ *   STANDARD,AUTO_STOP,WINDOWS_SERVER_2022,us-east-1,9.75,0.30 */
+type Price struct {
+    ComputeType     string  `json:"computeType"`
+    RunningMode     string  `json:"runningMode"`
+    OperatingSystem string  `json:"operatingSystem"`
+    Region          string  `json:"region"`
+    Monthly         float64 `json:"monthly"`
+    Hourly          float64 `json:"hourly"`
+}
+
+type Table struct {
+    prices []Price
+    /* Hours of use per month assumed for the AutoStop workspaces */
+    autoStopHours float64
+}
+
+func NewTable(prices []Price, autoStopHours float64) *Table {
+    return &Table{
+        prices: prices,
+        autoStopHours: autoStopHours,
+    }
+}
+
+/* Load reads a pricing table from a JSON or CSV file. */
+func Load(path string, autoStopHours float64) (*Table, error) {
+    content, err := os.ReadFile(path)
+    if err != nil {
+        return nil, err
+    }
+    prices := []Price{}
+    if strings.EqualFold(filepath.Ext(path), ".csv") {
+        prices, err = parseCSV(string(content))
+    } else {
+        err = json.Unmarshal(content, &prices)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("Invalid pricing file %s: %s", path, err.Error())
+    }
+    return NewTable(prices, autoStopHours), nil
+}
+
+func parseCSV(content string) ([]Price, error) {
+    records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+    if err != nil {
+        return nil, err
+    }
+    prices := []Price{}
+    if len(records) == 0 {
+        return prices, nil
+    }
+    header := records[0]
+    for line, record := range records[1:] {
+        price := Price{}
+        for i, value := range record {
+            if i >= len(header) {
+                break
+            }
+            value = strings.TrimSpace(value)
+            switch strings.ToLower(strings.TrimSpace(header[i])) {
+            case "computetype":
+                price.ComputeType = value
+            case "runningmode":
+                price.RunningMode = value
+            case "operatingsystem":
+                price.OperatingSystem = value
+            case "region":
+                price.Region = value
+            case "monthly", "hourly":
+                rate := 0.0
+                if value != "" {
+                    rate, err = strconv.ParseFloat(value, 64)
+                    if err != nil {
+                        return nil, fmt.Errorf("line %d: invalid rate %s", line + 2, value)
+                    }
+                }
+                if strings.EqualFold(strings.TrimSpace(header[i]), "monthly") {
+                    price.Monthly = rate
+                } else {
+                    price.Hourly = rate
+                }
+            }
+        }
+        prices = append(prices, price)
+    }
+    return prices, nil
+}
+
+func isAny(criterion string) bool {
+    criterion = strings.TrimSpace(criterion)
+    return criterion == "" || criterion == "*"
+}
+
+func matches(criterion string, value string) bool {
+    return isAny(criterion) || strings.ToUpper(strings.TrimSpace(criterion)) == strings.ToUpper(strings.TrimSpace(value))
+}
+
+/* Lookup returns the most specific price matching the workspace, or nil. */
+func (t *Table) Lookup(computeType string, runningMode string, operatingSystem string, region string) *Price {
+    if t == nil {
+        return nil
+    }
+    var best *Price
+    bestScore := -1
+    for i, price := range t.prices {
+        if !matches(price.ComputeType, computeType) || !matches(price.RunningMode, runningMode) ||
+            !matches(price.OperatingSystem, operatingSystem) || !matches(price.Region, region) {
+            continue
+        }
+        score := 0
+        for _, criterion := range []string{ price.ComputeType, price.RunningMode, price.OperatingSystem, price.Region } {
+            if !isAny(criterion) {
+                score++
+            }
+        }
+        if score > bestScore {
+            best = &t.prices[i]
+            bestScore = score
+        }
+    }
+    return best
+}
+
+/* MonthlyCost is the monthly rate, plus the hourly rate for the assumed
+ * hours of use of the AutoStop workspaces. */
+func (t *Table) MonthlyCost(price *Price, runningMode string) float64 {
+    cost := price.Monthly
+    if !strings.EqualFold(runningMode, "ALWAYS_ON") {
+        cost += price.Hourly * t.autoStopHours
+    }
+    return cost
+}
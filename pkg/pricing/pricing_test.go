@@ -0,0 +1,81 @@
+package pricing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	table := NewTable([]Price{
+		{Monthly: 1},
+		{ComputeType: "STANDARD", Monthly: 2},
+		{ComputeType: "STANDARD", RunningMode: "ALWAYS_ON", Monthly: 3},
+		{ComputeType: "STANDARD", RunningMode: "ALWAYS_ON", OperatingSystem: "WINDOWS_SERVER_2022", Monthly: 4},
+		{ComputeType: "STANDARD", RunningMode: "ALWAYS_ON", OperatingSystem: " windows_server_2022 ", Region: "us-east-1", Monthly: 5},
+		{ComputeType: "*", RunningMode: "*", OperatingSystem: "WINDOWS", Region: "*", Monthly: 6},
+	}, 80)
+	tests := []struct {
+		computeType     string
+		runningMode     string
+		operatingSystem string
+		region          string
+		want            float64
+	}{
+		{"STANDARD", "ALWAYS_ON", "WINDOWS_SERVER_2022", "us-east-1", 5},
+		{"STANDARD", "ALWAYS_ON", "WINDOWS_SERVER_2022", "eu-west-1", 4},
+		{"STANDARD", "ALWAYS_ON", "WINDOWS_SERVER_2019", "us-east-1", 3},
+		{"standard", "AUTO_STOP", "UBUNTU_22_04", "us-east-1", 2},
+		{"PERFORMANCE", "AUTO_STOP", "WINDOWS_SERVER_2022", "us-east-1", 1},
+		/* WINDOWS only matches WINDOWS, not WINDOWS_SERVER_2022 */
+		{"PERFORMANCE", "AUTO_STOP", "WINDOWS", "us-east-1", 6},
+	}
+	for _, tt := range tests {
+		price := table.Lookup(tt.computeType, tt.runningMode, tt.operatingSystem, tt.region)
+		if price == nil || price.Monthly != tt.want {
+			t.Errorf("%s %s %s %s: got %+v, want the monthly price %v", tt.computeType, tt.runningMode, tt.operatingSystem, tt.region, price, tt.want)
+		}
+	}
+
+	table = NewTable([]Price{{ComputeType: "STANDARD", Monthly: 2}}, 80)
+	if price := table.Lookup("POWER", "ALWAYS_ON", "WINDOWS_SERVER_2022", "us-east-1"); price != nil {
+		t.Errorf("expected no price, got %+v", price)
+	}
+	var missing *Table
+	if price := missing.Lookup("STANDARD", "ALWAYS_ON", "WINDOWS_SERVER_2022", "us-east-1"); price != nil {
+		t.Errorf("a missing table must have no price, got %+v", price)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	prices, err := parseCSV("computeType, RunningMode,operatingSystem,region,monthly,hourly,comment\n" +
+		"STANDARD,ALWAYS_ON,WINDOWS_SERVER_2022,us-east-1,35,,list price\n" +
+		" STANDARD ,AUTO_STOP,WINDOWS_SERVER_2022,us-east-1,9.75, 0.30 ,\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Price{
+		{ComputeType: "STANDARD", RunningMode: "ALWAYS_ON", OperatingSystem: "WINDOWS_SERVER_2022", Region: "us-east-1", Monthly: 35},
+		{ComputeType: "STANDARD", RunningMode: "AUTO_STOP", OperatingSystem: "WINDOWS_SERVER_2022", Region: "us-east-1", Monthly: 9.75, Hourly: 0.30},
+	}
+	if !reflect.DeepEqual(prices, want) {
+		t.Fatalf("unexpected prices\ngot  %+v\nwant %+v", prices, want)
+	}
+
+	if prices, err = parseCSV(""); err != nil || len(prices) != 0 {
+		t.Fatalf("an empty file must give no price, got %+v, %v", prices, err)
+	}
+	if _, err = parseCSV("computeType,monthly\nSTANDARD,35\nPOWER,cheap\n"); err == nil || err.Error() != "line 3: invalid rate cheap" {
+		t.Fatalf("expected an invalid rate on line 3, got %v", err)
+	}
+}
+
+func TestMonthlyCost(t *testing.T) {
+	table := NewTable(nil, 80)
+	price := &Price{Monthly: 9.75, Hourly: 0.30}
+	if cost := table.MonthlyCost(price, "AUTO_STOP"); cost != 9.75+0.30*80 {
+		t.Errorf("AutoStop: got %v, want the monthly rate plus 80 hours", cost)
+	}
+	if cost := table.MonthlyCost(price, "always_on"); cost != 9.75 {
+		t.Errorf("AlwaysOn: got %v, want the monthly rate only", cost)
+	}
+}
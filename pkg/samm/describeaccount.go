@@ -0,0 +1,115 @@
+package samm
+
+import (
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+/* DescribeAccount returns a single element with the account settings. */
+type SammAccount struct {
+    attributes map[string]interface{}
+    defaultFieldList []string
+    elements []interface{}
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammAccount(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammAccount {
+    return SammAccount{
+        attributes: map[string]interface{} {
+            "DedicatedTenancyAccountType": []*string{},
+            "DedicatedTenancyManagementCidrRange": []*string{},
+            "DedicatedTenancySupport": []*string{},
+        },
+        defaultFieldList: []string {
+            "DedicatedTenancySupport",
+            "DedicatedTenancyAccountType",
+            "DedicatedTenancyManagementCidrRange",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammAccount) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*workspaces.DescribeAccountOutput)
+    switch name {
+    case "DedicatedTenancyAccountType":
+        field.Append(object.DedicatedTenancyAccountType)
+    case "DedicatedTenancyManagementCidrRange":
+        field.Append(object.DedicatedTenancyManagementCidrRange)
+    case "DedicatedTenancySupport":
+        field.Append(object.DedicatedTenancySupport)
+    }
+}
+
+func (samm SammAccount) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammAccount) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+/* The account has no AWS side filter. */
+func (samm SammAccount) ClientFilterConditions() []models.FilterCondition {
+    return samm.filterConditions
+}
+
+func (samm SammAccount) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammAccount) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammAccount) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammAccount) NextToken() *string {
+    return samm.nextToken
+}
+
+/* DescribeAccount is not paginated and takes no filters. */
+func (samm *SammAccount) Query(elements []interface{}) ([]interface{}, *string, error) {
+    awsoutput, err := samm.svc.DescribeAccount(&workspaces.DescribeAccountInput{})
+    if (err != nil) {
+        log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+        return elements, nil, err
+    }
+    elements = append(elements, awsoutput)
+    return elements, nil, nil
+}
+
+func (samm *SammAccount) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+
+    if cacheIsValid {
+        samm.elements = elements
+        return nil
+    }
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements))
+    return err
+}
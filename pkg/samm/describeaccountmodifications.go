@@ -0,0 +1,163 @@
+package samm
+
+import (
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammAccountModification struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeAccountModificationsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammAccountModification(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammAccountModification {
+    return SammAccountModification{
+        attributes: map[string]interface{} {
+            "DedicatedTenancyManagementCidrRange": []*string{},
+            "DedicatedTenancySupport": []*string{},
+            "ErrorCode": []*string{},
+            "ErrorMessage": []*string{},
+            "ModificationState": []*string{},
+            "StartTime": []*time.Time{},
+        },
+        defaultFieldList: []string {
+            "StartTime",
+            "ModificationState",
+            "DedicatedTenancySupport",
+            "DedicatedTenancyManagementCidrRange",
+            "ErrorCode",
+            "ErrorMessage",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammAccountModification) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*workspaces.AccountModification)
+    switch name {
+    case "DedicatedTenancyManagementCidrRange":
+        field.Append(object.DedicatedTenancyManagementCidrRange)
+    case "DedicatedTenancySupport":
+        field.Append(object.DedicatedTenancySupport)
+    case "ErrorCode":
+        field.Append(object.ErrorCode)
+    case "ErrorMessage":
+        field.Append(object.ErrorMessage)
+    case "ModificationState":
+        field.Append(object.ModificationState)
+    case "StartTime":
+        field.Append(object.StartTime)
+    }
+}
+
+func (samm SammAccountModification) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammAccountModification) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammAccountModification) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammAccountModification) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeAccountModificationsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    _, samm.clientFilterConditions = splitConditions(samm.filterConditions)
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammAccountModification) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammAccountModification) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammAccountModification) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammAccountModification) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammAccountModification) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeAccountModificationsOutput
+
+        awsoutput, err = samm.svc.DescribeAccountModifications(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.AccountModifications {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeAccountModifications Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammAccountModification) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
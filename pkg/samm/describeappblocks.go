@@ -0,0 +1,223 @@
+package samm
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammAppBlock struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeAppBlocksInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammAppBlock(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammAppBlock {
+    samm := SammAppBlock{
+        attributes: map[string]interface{} {
+            "AppBlockErrors": []string{},
+            "Arn": []*string{},
+            "CreatedTime": []*time.Time{},
+            "Description": []*string{},
+            "DisplayName": []*string{},
+            "Name": []*string{},
+            "PackagingType": []*string{},
+            "PostSetupScriptDetails": []string{},
+            "SetupScriptDetails": []string{},
+            "SourceS3Location": []string{},
+            "State": []*string{},
+        },
+        defaultFieldList: []string {
+            "Name",
+            "DisplayName",
+            "PackagingType",
+            "State",
+            "Arn",
+            "CreatedTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.AppBlock{}, "AppBlockErrors", "PostSetupScriptDetails", "SetupScriptDetails", "SourceS3Location")
+    return samm
+}
+
+func (samm SammAppBlock) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.AppBlock)
+    switch name {
+    case "AppBlockErrors":
+        temp, err := json.Marshal(object.AppBlockErrors)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "Arn":
+        field.Append(object.Arn)
+    case "CreatedTime":
+        field.Append(object.CreatedTime)
+    case "Description":
+        field.Append(object.Description)
+    case "DisplayName":
+        field.Append(object.DisplayName)
+    case "Name":
+        field.Append(object.Name)
+    case "PackagingType":
+        field.Append(object.PackagingType)
+    case "PostSetupScriptDetails":
+        temp, err := json.Marshal(object.PostSetupScriptDetails)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "SetupScriptDetails":
+        temp, err := json.Marshal(object.SetupScriptDetails)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "SourceS3Location":
+        temp, err := json.Marshal(object.SourceS3Location)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "State":
+        field.Append(object.State)
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammAppBlock) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammAppBlock) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammAppBlock) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammAppBlock) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeAppBlocksInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    arns := []*string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "Arn")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "Arn":
+            arns = append(arns, aws.String(filterCondition.Value))
+        }
+    }
+    if len(arns) > 0 {
+        log.DefaultLogger.Debug("Filter by arns.", "arns_count", len(arns))
+        samm.filter.SetArns(arns)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammAppBlock) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammAppBlock) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammAppBlock) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammAppBlock) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammAppBlock) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammAppBlock) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeAppBlocksOutput
+
+        awsoutput, err = samm.svc.DescribeAppBlocks(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.AppBlocks {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeAppBlocks Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammAppBlock) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
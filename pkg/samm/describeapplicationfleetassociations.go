@@ -0,0 +1,154 @@
+package samm
+
+import (
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammApplicationFleetAssociation struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeApplicationFleetAssociationsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammApplicationFleetAssociation(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammApplicationFleetAssociation {
+    return SammApplicationFleetAssociation{
+        attributes: map[string]interface{} {
+            "ApplicationArn": []*string{},
+            "FleetName": []*string{},
+        },
+        defaultFieldList: []string {
+            "ApplicationArn",
+            "FleetName",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammApplicationFleetAssociation) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.ApplicationFleetAssociation)
+    switch name {
+    case "ApplicationArn":
+        field.Append(object.ApplicationArn)
+    case "FleetName":
+        field.Append(object.FleetName)
+    }
+}
+
+func (samm SammApplicationFleetAssociation) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammApplicationFleetAssociation) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammApplicationFleetAssociation) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammApplicationFleetAssociation) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeApplicationFleetAssociationsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "ApplicationArn", "FleetName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "ApplicationArn":
+            samm.filter.SetApplicationArn(filterCondition.Value)
+        case "FleetName":
+            samm.filter.SetFleetName(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammApplicationFleetAssociation) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammApplicationFleetAssociation) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammApplicationFleetAssociation) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammApplicationFleetAssociation) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammApplicationFleetAssociation) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeApplicationFleetAssociationsOutput
+
+        awsoutput, err = samm.svc.DescribeApplicationFleetAssociations(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.ApplicationFleetAssociations {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeApplicationFleetAssociations Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammApplicationFleetAssociation) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,227 @@
+package samm
+
+import (
+    "encoding/json"
+    "strings"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammApplication struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeApplicationsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammApplication(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammApplication {
+    samm := SammApplication{
+        attributes: map[string]interface{} {
+            "AppBlockArn": []*string{},
+            "Arn": []*string{},
+            "CreatedTime": []*time.Time{},
+            "Description": []*string{},
+            "DisplayName": []*string{},
+            "Enabled": []*bool{},
+            "IconS3Location": []string{},
+            "IconURL": []*string{},
+            "InstanceFamilies": []string{},
+            "LaunchParameters": []*string{},
+            "LaunchPath": []*string{},
+            "Metadata": []string{},
+            "Name": []*string{},
+            "Platforms": []string{},
+            "WorkingDirectory": []*string{},
+        },
+        defaultFieldList: []string {
+            "Name",
+            "DisplayName",
+            "Enabled",
+            "Platforms",
+            "LaunchPath",
+            "AppBlockArn",
+            "CreatedTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.Application{}, "IconS3Location")
+    return samm
+}
+
+func (samm SammApplication) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.Application)
+    switch name {
+    case "AppBlockArn":
+        field.Append(object.AppBlockArn)
+    case "Arn":
+        field.Append(object.Arn)
+    case "CreatedTime":
+        field.Append(object.CreatedTime)
+    case "Description":
+        field.Append(object.Description)
+    case "DisplayName":
+        field.Append(object.DisplayName)
+    case "Enabled":
+        field.Append(object.Enabled)
+    case "IconS3Location":
+        temp, err := json.Marshal(object.IconS3Location)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "IconURL":
+        field.Append(object.IconURL)
+    case "InstanceFamilies":
+        field.Append(strings.Join(aws.StringValueSlice(object.InstanceFamilies), ","))
+    case "LaunchParameters":
+        field.Append(object.LaunchParameters)
+    case "LaunchPath":
+        field.Append(object.LaunchPath)
+    case "Metadata":
+        temp, err := json.Marshal(object.Metadata)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "Name":
+        field.Append(object.Name)
+    case "Platforms":
+        field.Append(strings.Join(aws.StringValueSlice(object.Platforms), ","))
+    case "WorkingDirectory":
+        field.Append(object.WorkingDirectory)
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammApplication) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammApplication) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammApplication) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammApplication) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeApplicationsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    arns := []*string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "Arn")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "Arn":
+            arns = append(arns, aws.String(filterCondition.Value))
+        }
+    }
+    if len(arns) > 0 {
+        log.DefaultLogger.Debug("Filter by arns.", "arns_count", len(arns))
+        samm.filter.SetArns(arns)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammApplication) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammApplication) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammApplication) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammApplication) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammApplication) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammApplication) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeApplicationsOutput
+
+        awsoutput, err = samm.svc.DescribeApplications(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Applications {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeApplications Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammApplication) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
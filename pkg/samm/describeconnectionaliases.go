@@ -0,0 +1,190 @@
+package samm
+
+import (
+    "encoding/json"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammConnectionAlias struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeConnectionAliasesInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammConnectionAlias(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammConnectionAlias {
+    samm := SammConnectionAlias{
+        attributes: map[string]interface{} {
+            "AliasId": []*string{},
+            "Associations": []string{},
+            "ConnectionString": []*string{},
+            "OwnerAccountId": []*string{},
+            "State": []*string{},
+        },
+        defaultFieldList: []string {
+            "AliasId",
+            "ConnectionString",
+            "State",
+            "OwnerAccountId",
+            "Associations",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &workspaces.ConnectionAlias{}, "Associations")
+    return samm
+}
+
+func (samm SammConnectionAlias) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*workspaces.ConnectionAlias)
+    switch name {
+    case "AliasId":
+        field.Append(object.AliasId)
+    case "Associations":
+        temp, err := json.Marshal(object.Associations)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "ConnectionString":
+        field.Append(object.ConnectionString)
+    case "OwnerAccountId":
+        field.Append(object.OwnerAccountId)
+    case "State":
+        field.Append(object.State)
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammConnectionAlias) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammConnectionAlias) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammConnectionAlias) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammConnectionAlias) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeConnectionAliasesInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    aliasIds := []*string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "AliasId", "ResourceId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "AliasId":
+            aliasIds = append(aliasIds, aws.String(filterCondition.Value))
+        case "ResourceId":
+            samm.filter.SetResourceId(filterCondition.Value)
+        }
+    }
+    if len(aliasIds) > 0 {
+        log.DefaultLogger.Debug("Filter by aliasIds.", "aliasIds_count", len(aliasIds))
+        samm.filter.SetAliasIds(aliasIds)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammConnectionAlias) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammConnectionAlias) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammConnectionAlias) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammConnectionAlias) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammConnectionAlias) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammConnectionAlias) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeConnectionAliasesOutput
+
+        awsoutput, err = samm.svc.DescribeConnectionAliases(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.ConnectionAliases {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeConnectionAliases Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammConnectionAlias) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
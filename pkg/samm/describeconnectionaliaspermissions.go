@@ -0,0 +1,168 @@
+package samm
+
+import (
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+/* Element of DescribeConnectionAliasPermissions. */
+type SammConnectionAliasPermissionItem struct {
+    AliasId *string
+    AllowAssociation *bool
+    SharedAccountId *string
+}
+
+type SammConnectionAliasPermission struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeConnectionAliasPermissionsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammConnectionAliasPermission(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammConnectionAliasPermission {
+    return SammConnectionAliasPermission{
+        attributes: map[string]interface{} {
+            "AliasId": []*string{},
+            "AllowAssociation": []*bool{},
+            "SharedAccountId": []*string{},
+        },
+        defaultFieldList: []string {
+            "AliasId",
+            "SharedAccountId",
+            "AllowAssociation",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammConnectionAliasPermission) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*SammConnectionAliasPermissionItem)
+    switch name {
+    case "AliasId":
+        field.Append(object.AliasId)
+    case "AllowAssociation":
+        field.Append(object.AllowAssociation)
+    case "SharedAccountId":
+        field.Append(object.SharedAccountId)
+    }
+}
+
+func (samm SammConnectionAliasPermission) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammConnectionAliasPermission) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammConnectionAliasPermission) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammConnectionAliasPermission) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeConnectionAliasPermissionsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    /* AliasId is mandatory for DescribeConnectionAliasPermissions */
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "AliasId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "AliasId":
+            samm.filter.SetAliasId(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammConnectionAliasPermission) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammConnectionAliasPermission) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammConnectionAliasPermission) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammConnectionAliasPermission) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammConnectionAliasPermission) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeConnectionAliasPermissionsOutput
+
+        awsoutput, err = samm.svc.DescribeConnectionAliasPermissions(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.ConnectionAliasPermissions {
+            elements = append(elements, &SammConnectionAliasPermissionItem{
+                AliasId: awsoutput.AliasId,
+                AllowAssociation: e.AllowAssociation,
+                SharedAccountId: e.SharedAccountId,
+            })
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeConnectionAliasPermissions Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammConnectionAliasPermission) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -1,7 +1,6 @@
 package samm
 
 import (
-    "fmt"
     "encoding/json"
     "time"
 
@@ -16,17 +15,19 @@ import (
 
 type SammDirectoryConfigs struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter appstream.DescribeDirectoryConfigsInput
     filterConditions []models.FilterCondition
     limit int
+    nestedFieldList []string
     nextToken *string
     svc *appstream.AppStream
 }
 
 func NewSammDirectoryConfigs(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammDirectoryConfigs {
-    return SammDirectoryConfigs{
+    samm := SammDirectoryConfigs{
         attributes: map[string]interface{} {
 			"CertificateBasedAuthProperties": []string{},
 			"CreatedTime": []*time.Time{},
@@ -45,6 +46,8 @@ func NewSammDirectoryConfigs(svc *appstream.AppStream, filterConditions []models
         limit: Limit,
         svc: svc,
     }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.DirectoryConfig{}, "CertificateBasedAuthProperties")
+    return samm
 }
 
 func (samm SammDirectoryConfigs) AppendData(elementIndex int, field *data.Field, name string) {
@@ -75,6 +78,10 @@ func (samm SammDirectoryConfigs) AppendData(elementIndex int, field *data.Field,
             return
         }
         field.Append(string(temp))
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
     }
 }
 
@@ -90,23 +97,27 @@ func (samm SammDirectoryConfigs) AttributeType(attributeName string) (interface{
     return attr, ok
 }
 
+func (samm SammDirectoryConfigs) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammDirectoryConfigs) createFilter(NextToken *string) {
 
     if NextToken != nil {
         samm.filter.SetNextToken(*NextToken)
     }
     directorynames := []*string{}
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "Name")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "Name":
             value := filterCondition.Value
             directorynames = append(directorynames, &value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
         }
     }
     if len(directorynames) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by directorynames.", "ndirectorynames_count", len(directorynames)))
+        log.DefaultLogger.Debug("Filter by directorynames.", "ndirectorynames_count", len(directorynames))
         samm.filter.SetDirectoryNames(directorynames)
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -124,6 +135,10 @@ func (samm SammDirectoryConfigs) Len() int {
     return len(samm.elements)
 }
 
+func (samm SammDirectoryConfigs) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
 func (samm SammDirectoryConfigs) NextToken() *string {
     return samm.nextToken
 }
@@ -155,7 +170,6 @@ func (samm *SammDirectoryConfigs) Query(elements []interface{}) ([]interface{},
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammDirectoryConfigs) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -166,6 +180,7 @@ func (samm *SammDirectoryConfigs) UpdateElements(cachedElements interface{}, nex
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
@@ -0,0 +1,193 @@
+package samm
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammEntitlement struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeEntitlementsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammEntitlement(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammEntitlement {
+    samm := SammEntitlement{
+        attributes: map[string]interface{} {
+            "AppVisibility": []*string{},
+            "Attributes": []string{},
+            "CreatedTime": []*time.Time{},
+            "Description": []*string{},
+            "LastModifiedTime": []*time.Time{},
+            "Name": []*string{},
+            "StackName": []*string{},
+        },
+        defaultFieldList: []string {
+            "Name",
+            "StackName",
+            "AppVisibility",
+            "Attributes",
+            "Description",
+            "LastModifiedTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.Entitlement{}, "Attributes")
+    return samm
+}
+
+func (samm SammEntitlement) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.Entitlement)
+    switch name {
+    case "AppVisibility":
+        field.Append(object.AppVisibility)
+    case "Attributes":
+        temp, err := json.Marshal(object.Attributes)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "CreatedTime":
+        field.Append(object.CreatedTime)
+    case "Description":
+        field.Append(object.Description)
+    case "LastModifiedTime":
+        field.Append(object.LastModifiedTime)
+    case "Name":
+        field.Append(object.Name)
+    case "StackName":
+        field.Append(object.StackName)
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammEntitlement) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammEntitlement) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammEntitlement) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammEntitlement) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeEntitlementsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    /* StackName is mandatory for DescribeEntitlements */
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "StackName", "Name")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "StackName":
+            samm.filter.SetStackName(filterCondition.Value)
+        case "Name":
+            samm.filter.SetName(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammEntitlement) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammEntitlement) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammEntitlement) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammEntitlement) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammEntitlement) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammEntitlement) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeEntitlementsOutput
+
+        awsoutput, err = samm.svc.DescribeEntitlements(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Entitlements {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeEntitlements Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammEntitlement) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -1,7 +1,6 @@
 package samm
 
 import (
-    "fmt"
     "encoding/json"
     "time"
 
@@ -16,17 +15,19 @@ import (
 
 type SammFleet struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter *appstream.DescribeFleetsInput
     filterConditions []models.FilterCondition
     limit int
+    nestedFieldList []string
     nextToken *string
     svc *appstream.AppStream
 }
 
 func NewSammFleet(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammFleet {
-    return SammFleet{
+    samm := SammFleet{
         attributes: map[string]interface{} {
             "Arn":  []*string{},
             "ComputeCapacityStatus": []string{},
@@ -72,6 +73,8 @@ func NewSammFleet(svc *appstream.AppStream, filterConditions []models.FilterCond
         limit: Limit,
         svc: svc,
     }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.Fleet{}, "ComputeCapacityStatus", "DomainJoinInfo", "SessionScriptS3Location", "VpcConfig")
+    return samm
 }
 
 func (samm SammFleet) AppendData(elementIndex int, field *data.Field, name string) {
@@ -145,6 +148,10 @@ func (samm SammFleet) AppendData(elementIndex int, field *data.Field, name strin
             return
         }
         field.Append(string(temp))
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
     }
 }
 
@@ -160,6 +167,10 @@ func (samm SammFleet) AttributeType(attributeName string) (interface{}, bool) {
     return attr, ok
 }
 
+func (samm SammFleet) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammFleet) createFilter(NextToken *string) {
     samm.filter = &appstream.DescribeFleetsInput{}
 
@@ -167,17 +178,17 @@ func (samm *SammFleet) createFilter(NextToken *string) {
         samm.filter.SetNextToken(*NextToken)
     }
     names := []*string{}
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "FleetName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "FleetName":
             value := filterCondition.Value
             names = append(names, &value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
         }
     }
     if len(names) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by names.", "names_count", len(names)))
+        log.DefaultLogger.Debug("Filter by names.", "names_count", len(names))
         samm.filter.SetNames(names)
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -195,6 +206,10 @@ func (samm SammFleet) Len() int {
     return len(samm.elements)
 }
 
+func (samm SammFleet) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
 func (samm SammFleet) NextToken() *string {
     return samm.nextToken
 }
@@ -226,7 +241,6 @@ func (samm *SammFleet) Query(elements []interface{}) ([]interface{}, *string, er
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammFleet) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -237,6 +251,7 @@ func (samm *SammFleet) UpdateElements(cachedElements interface{}, nextToken *str
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
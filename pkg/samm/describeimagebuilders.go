@@ -0,0 +1,257 @@
+package samm
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammImageBuilder struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeImageBuildersInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammImageBuilder(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammImageBuilder {
+    samm := SammImageBuilder{
+        attributes: map[string]interface{} {
+            "AccessEndpoints": []string{},
+            "AppstreamAgentVersion": []*string{},
+            "Arn": []*string{},
+            "CreatedTime": []*time.Time{},
+            "Description": []*string{},
+            "DisplayName": []*string{},
+            "DomainJoinInfo": []string{},
+            "EnableDefaultInternetAccess": []*bool{},
+            "IamRoleArn": []*string{},
+            "ImageArn": []*string{},
+            "ImageBuilderErrors": []string{},
+            "InstanceType": []*string{},
+            "Name": []*string{},
+            "NetworkAccessConfiguration": []string{},
+            "Platform": []*string{},
+            "State": []*string{},
+            "StateChangeReason": []string{},
+            "VpcConfig": []string{},
+        },
+        defaultFieldList: []string {
+            "Name",
+            "DisplayName",
+            "Platform",
+            "InstanceType",
+            "State",
+            "ImageArn",
+            "AppstreamAgentVersion",
+            "CreatedTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.ImageBuilder{}, "AccessEndpoints", "DomainJoinInfo", "ImageBuilderErrors", "NetworkAccessConfiguration", "StateChangeReason", "VpcConfig")
+    return samm
+}
+
+func (samm SammImageBuilder) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.ImageBuilder)
+    switch name {
+    case "AccessEndpoints":
+        temp, err := json.Marshal(object.AccessEndpoints)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "AppstreamAgentVersion":
+        field.Append(object.AppstreamAgentVersion)
+    case "Arn":
+        field.Append(object.Arn)
+    case "CreatedTime":
+        field.Append(object.CreatedTime)
+    case "Description":
+        field.Append(object.Description)
+    case "DisplayName":
+        field.Append(object.DisplayName)
+    case "DomainJoinInfo":
+        temp, err := json.Marshal(object.DomainJoinInfo)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "EnableDefaultInternetAccess":
+        field.Append(object.EnableDefaultInternetAccess)
+    case "IamRoleArn":
+        field.Append(object.IamRoleArn)
+    case "ImageArn":
+        field.Append(object.ImageArn)
+    case "ImageBuilderErrors":
+        temp, err := json.Marshal(object.ImageBuilderErrors)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "InstanceType":
+        field.Append(object.InstanceType)
+    case "Name":
+        field.Append(object.Name)
+    case "NetworkAccessConfiguration":
+        temp, err := json.Marshal(object.NetworkAccessConfiguration)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "Platform":
+        field.Append(object.Platform)
+    case "State":
+        field.Append(object.State)
+    case "StateChangeReason":
+        temp, err := json.Marshal(object.StateChangeReason)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "VpcConfig":
+        temp, err := json.Marshal(object.VpcConfig)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammImageBuilder) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammImageBuilder) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammImageBuilder) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammImageBuilder) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeImageBuildersInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    names := []*string{}
+    /* Platform is not supported by the API and is filtered client side */
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "Name")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "Name":
+            names = append(names, aws.String(filterCondition.Value))
+        }
+    }
+    if len(names) > 0 {
+        log.DefaultLogger.Debug("Filter by names.", "names_count", len(names))
+        samm.filter.SetNames(names)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammImageBuilder) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammImageBuilder) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammImageBuilder) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammImageBuilder) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammImageBuilder) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammImageBuilder) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeImageBuildersOutput
+
+        awsoutput, err = samm.svc.DescribeImageBuilders(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.ImageBuilders {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeImageBuilders Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammImageBuilder) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,254 @@
+package samm
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammImage struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeImagesInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammImage(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammImage {
+    samm := SammImage{
+        attributes: map[string]interface{} {
+            "Applications": []string{},
+            "AppstreamAgentVersion": []*string{},
+            "Arn": []*string{},
+            "BaseImageArn": []*string{},
+            "CreatedTime": []*time.Time{},
+            "Description": []*string{},
+            "DisplayName": []*string{},
+            "ImageBuilderName": []*string{},
+            "ImageBuilderSupported": []*bool{},
+            "ImageErrors": []string{},
+            "ImagePermissions": []string{},
+            "Name": []*string{},
+            "Platform": []*string{},
+            "PublicBaseImageReleasedDate": []*time.Time{},
+            "State": []*string{},
+            "StateChangeReason": []string{},
+            "Visibility": []*string{},
+        },
+        defaultFieldList: []string {
+            "Name",
+            "DisplayName",
+            "Platform",
+            "Visibility",
+            "State",
+            "AppstreamAgentVersion",
+            "ImageBuilderName",
+            "CreatedTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.Image{}, "Applications", "ImageErrors", "ImagePermissions", "StateChangeReason")
+    return samm
+}
+
+func (samm SammImage) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.Image)
+    switch name {
+    case "Applications":
+        temp, err := json.Marshal(object.Applications)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "AppstreamAgentVersion":
+        field.Append(object.AppstreamAgentVersion)
+    case "Arn":
+        field.Append(object.Arn)
+    case "BaseImageArn":
+        field.Append(object.BaseImageArn)
+    case "CreatedTime":
+        field.Append(object.CreatedTime)
+    case "Description":
+        field.Append(object.Description)
+    case "DisplayName":
+        field.Append(object.DisplayName)
+    case "ImageBuilderName":
+        field.Append(object.ImageBuilderName)
+    case "ImageBuilderSupported":
+        field.Append(object.ImageBuilderSupported)
+    case "ImageErrors":
+        temp, err := json.Marshal(object.ImageErrors)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "ImagePermissions":
+        temp, err := json.Marshal(object.ImagePermissions)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "Name":
+        field.Append(object.Name)
+    case "Platform":
+        field.Append(object.Platform)
+    case "PublicBaseImageReleasedDate":
+        field.Append(object.PublicBaseImageReleasedDate)
+    case "State":
+        field.Append(object.State)
+    case "StateChangeReason":
+        temp, err := json.Marshal(object.StateChangeReason)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "Visibility":
+        field.Append(object.Visibility)
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammImage) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammImage) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammImage) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammImage) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeImagesInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    names := []*string{}
+    arns := []*string{}
+    /* The Type of DescribeImages is the Visibility of the images. Platform
+     * is not supported by the API and is filtered client side. */
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "Name", "Arn", "Visibility")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "Name":
+            names = append(names, aws.String(filterCondition.Value))
+        case "Arn":
+            arns = append(arns, aws.String(filterCondition.Value))
+        case "Visibility":
+            samm.filter.SetType(filterCondition.Value)
+        }
+    }
+    if len(names) > 0 {
+        log.DefaultLogger.Debug("Filter by names.", "names_count", len(names))
+        samm.filter.SetNames(names)
+    }
+    if len(arns) > 0 {
+        log.DefaultLogger.Debug("Filter by arns.", "arns_count", len(arns))
+        samm.filter.SetArns(arns)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammImage) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammImage) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammImage) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammImage) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammImage) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammImage) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeImagesOutput
+
+        awsoutput, err = samm.svc.DescribeImages(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Images {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeImages Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammImage) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,225 @@
+package samm
+
+import (
+    "strings"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+/* DescribeIpGroups returns one element per rule. Groups without rules
+ * are returned as a single element with empty IpRule. */
+type SammIpGroupRule struct {
+    GroupId *string
+    GroupName *string
+    GroupDesc *string
+    IpRule *string
+    RuleDesc *string
+    DirectoryIds []*string
+}
+
+type SammIpGroup struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeIpGroupsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammIpGroup(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammIpGroup {
+    return SammIpGroup{
+        attributes: map[string]interface{} {
+            "DirectoryIds": []string{},
+            "GroupDesc": []*string{},
+            "GroupId": []*string{},
+            "GroupName": []*string{},
+            "IpRule": []*string{},
+            "RuleDesc": []*string{},
+        },
+        defaultFieldList: []string {
+            "GroupId",
+            "GroupName",
+            "IpRule",
+            "RuleDesc",
+            "DirectoryIds",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammIpGroup) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*SammIpGroupRule)
+    switch name {
+    case "DirectoryIds":
+        field.Append(strings.Join(aws.StringValueSlice(object.DirectoryIds), ","))
+    case "GroupDesc":
+        field.Append(object.GroupDesc)
+    case "GroupId":
+        field.Append(object.GroupId)
+    case "GroupName":
+        field.Append(object.GroupName)
+    case "IpRule":
+        field.Append(object.IpRule)
+    case "RuleDesc":
+        field.Append(object.RuleDesc)
+    }
+}
+
+func (samm SammIpGroup) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammIpGroup) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammIpGroup) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammIpGroup) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeIpGroupsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    groupIds := []*string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "GroupId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "GroupId":
+            groupIds = append(groupIds, aws.String(filterCondition.Value))
+        }
+    }
+    if len(groupIds) > 0 {
+        log.DefaultLogger.Debug("Filter by GroupIds.", "ids_count", len(groupIds))
+        samm.filter.SetGroupIds(groupIds)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammIpGroup) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammIpGroup) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammIpGroup) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammIpGroup) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammIpGroup) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeIpGroupsOutput
+
+        awsoutput, err = samm.svc.DescribeIpGroups(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Result {
+            if len(e.UserRules) == 0 {
+                elements = append(elements, &SammIpGroupRule{
+                    GroupId: e.GroupId,
+                    GroupName: e.GroupName,
+                    GroupDesc: e.GroupDesc,
+                })
+            }
+            for _, rule := range e.UserRules {
+                elements = append(elements, &SammIpGroupRule{
+                    GroupId: e.GroupId,
+                    GroupName: e.GroupName,
+                    GroupDesc: e.GroupDesc,
+                    IpRule: rule.IpRule,
+                    RuleDesc: rule.RuleDesc,
+                })
+            }
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeIpGroups Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+/* Reverse lookup of the directories using each group. */
+func (samm *SammIpGroup) lookupDirectories(elements []interface{}) error {
+    sd := NewSammWorkspacesDirectory(samm.svc, []models.FilterCondition{}, -1)
+    err := sd.UpdateElements([]interface{}{}, nil, false)
+    if err != nil {
+        return err
+    }
+    directories := map[string][]*string{}
+    for _, e := range sd.Elements() {
+        directory := e.(*workspaces.WorkspaceDirectory)
+        for _, groupId := range directory.IpGroupIds {
+            directories[*groupId] = append(directories[*groupId], directory.DirectoryId)
+        }
+    }
+    for _, e := range elements {
+        rule := e.(*SammIpGroupRule)
+        rule.DirectoryIds = directories[aws.StringValue(rule.GroupId)]
+    }
+    return nil
+}
+
+func (samm *SammIpGroup) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    if lookupErr := samm.lookupDirectories(elements); lookupErr != nil {
+        log.DefaultLogger.Warn("Unable to lookup directories.", "error", lookupErr.Error())
+    }
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -16,17 +16,19 @@ import (
 
 type SammSession struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter *appstream.DescribeSessionsInput
     filterConditions []models.FilterCondition
     limit int
+    nestedFieldList []string
     nextToken *string
     svc *appstream.AppStream
 }
 
 func NewSammSession(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammSession {
-    return SammSession{
+    samm := SammSession{
         attributes: map[string]interface{} {
             "AuthenticationType": []*string{},
             "ConnectionState": []*string{},
@@ -57,6 +59,8 @@ func NewSammSession(svc *appstream.AppStream, filterConditions []models.FilterCo
         limit: Limit,
         svc: svc,
     }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.Session{}, "NetworkAccessConfiguration")
+    return samm
 }
 
 func (samm SammSession) AppendData(elementIndex int, field *data.Field, name string) {
@@ -89,6 +93,10 @@ func (samm SammSession) AppendData(elementIndex int, field *data.Field, name str
         field.Append(object.State)
     case "UserId":
         field.Append(object.UserId)
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
     }
 }
 
@@ -104,13 +112,19 @@ func (samm SammSession) AttributeType(attributeName string) (interface{}, bool)
     return attr, ok
 }
 
+func (samm SammSession) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammSession) createFilter(NextToken *string) {
     samm.filter = &appstream.DescribeSessionsInput{}
 
     if NextToken != nil {
         samm.filter.SetNextToken(*NextToken)
     }
-    for _, filterCondition := range samm.filterConditions {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "AuthenticationType", "FleetName", "InstanceId", "Limit", "StackName", "UserId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
         switch property := filterCondition.Property; property {
         case "AuthenticationType":
             samm.filter.SetAuthenticationType(filterCondition.Value)
@@ -129,8 +143,6 @@ func (samm *SammSession) createFilter(NextToken *string) {
             samm.filter.SetStackName(filterCondition.Value)
         case "UserId":
             samm.filter.SetUserId(filterCondition.Value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
         }
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -148,6 +160,10 @@ func (samm SammSession) Len() int {
     return len(samm.elements)
 }
 
+func (samm SammSession) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
 func (samm SammSession) NextToken() *string {
     return samm.nextToken
 }
@@ -179,7 +195,6 @@ func (samm *SammSession) Query(elements []interface{}) ([]interface{}, *string,
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammSession) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -190,6 +205,7 @@ func (samm *SammSession) UpdateElements(cachedElements interface{}, nextToken *s
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
@@ -1,7 +1,6 @@
 package samm
 
 import (
-    "fmt"
     "encoding/json"
     "time"
 
@@ -16,17 +15,19 @@ import (
 
 type SammStack struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter *appstream.DescribeStacksInput
     filterConditions []models.FilterCondition
     limit int
+    nestedFieldList []string
     nextToken *string
     svc *appstream.AppStream
 }
 
 func NewSammStack(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammStack {
-    return SammStack{
+    samm := SammStack{
         attributes: map[string]interface{} {
 			"AccessEndpoints": []string{},
 			"ApplicationSettings": []string{},
@@ -63,6 +64,8 @@ func NewSammStack(svc *appstream.AppStream, filterConditions []models.FilterCond
         limit: Limit,
         svc: svc,
     }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &appstream.Stack{}, "AccessEndpoints", "ApplicationSettings", "StackErrors", "StorageConnectors", "StreamingExperienceSettings", "UserSettings")
+    return samm
 }
 
 func (samm SammStack) AppendData(elementIndex int, field *data.Field, name string) {
@@ -131,6 +134,10 @@ func (samm SammStack) AppendData(elementIndex int, field *data.Field, name strin
             return
         }
         field.Append(string(temp))
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
     }
 }
 
@@ -146,6 +153,10 @@ func (samm SammStack) AttributeType(attributeName string) (interface{}, bool) {
     return attr, ok
 }
 
+func (samm SammStack) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammStack) createFilter(NextToken *string) {
     samm.filter = &appstream.DescribeStacksInput{}
 
@@ -153,17 +164,17 @@ func (samm *SammStack) createFilter(NextToken *string) {
         samm.filter.SetNextToken(*NextToken)
     }
     names := []*string{}
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "StackName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "StackName":
             value := filterCondition.Value
             names = append(names, &value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
         }
     }
     if len(names) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by names.", "names_count", len(names)))
+        log.DefaultLogger.Debug("Filter by names.", "names_count", len(names))
         samm.filter.SetNames(names)
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -181,6 +192,10 @@ func (samm SammStack) Len() int {
     return len(samm.elements)
 }
 
+func (samm SammStack) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
 func (samm SammStack) NextToken() *string {
     return samm.nextToken
 }
@@ -212,7 +227,6 @@ func (samm *SammStack) Query(elements []interface{}) ([]interface{}, *string, er
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammStack) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -223,6 +237,7 @@ func (samm *SammStack) UpdateElements(cachedElements interface{}, nextToken *str
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
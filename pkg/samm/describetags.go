@@ -0,0 +1,200 @@
+package samm
+
+import (
+    "sort"
+    "strings"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+/* Fields named "tag:<Key>" hold the value of the tag Key of each element. */
+const TagPrefix = "tag:"
+
+func IsTagField(name string) bool {
+    return strings.HasPrefix(name, TagPrefix)
+}
+
+func TagKey(name string) string {
+    return strings.TrimPrefix(name, TagPrefix)
+}
+
+/* NeedsTags returns true when any field or filter condition refers to a tag. */
+func NeedsTags(fieldList []string, filterConditions []models.FilterCondition) bool {
+    for _, fieldName := range fieldList {
+        if IsTagField(fieldName) {
+            return true
+        }
+    }
+    for _, filterCondition := range filterConditions {
+        if IsTagField(filterCondition.Property) {
+            return true
+        }
+    }
+    return false
+}
+
+/* ResourceTags maps a resource id to its tags. */
+type ResourceTags map[string]map[string]*string
+
+func (rt ResourceTags) Value(resourceId *string, key string) *string {
+    if resourceId == nil {
+        return nil
+    }
+    return rt[*resourceId][key]
+}
+
+/* Keys returns the sorted list of tag keys as field names. */
+func (rt ResourceTags) Keys() []string {
+    keys := map[string]bool{}
+    for _, tags := range rt {
+        for key := range tags {
+            keys[key] = true
+        }
+    }
+    out := []string{}
+    for key := range keys {
+        out = append(out, TagPrefix + key)
+    }
+    sort.Strings(out)
+    return out
+}
+
+type SammTag struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeTagsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammTag(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammTag {
+    return SammTag{
+        attributes: map[string]interface{} {
+            "Key": []*string{},
+            "Value": []*string{},
+        },
+        defaultFieldList: []string {
+            "Key",
+            "Value",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammTag) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*workspaces.Tag)
+    switch name {
+    case "Key":
+        field.Append(object.Key)
+    case "Value":
+        field.Append(object.Value)
+    }
+}
+
+func (samm SammTag) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammTag) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammTag) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammTag) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeTagsInput{}
+
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "ResourceId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "ResourceId":
+            samm.filter.SetResourceId(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammTag) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammTag) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammTag) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammTag) NextToken() *string {
+    return samm.nextToken
+}
+
+/* Map returns the tags as key/value pairs. */
+func (samm SammTag) Map() map[string]*string {
+    out := map[string]*string{}
+    for _, e := range samm.elements {
+        tag := e.(*workspaces.Tag)
+        out[aws.StringValue(tag.Key)] = tag.Value
+    }
+    return out
+}
+
+/* DescribeTags is not paginated. */
+func (samm *SammTag) Query(elements []interface{}) ([]interface{}, *string, error) {
+    awsoutput, err := samm.svc.DescribeTags(samm.filter)
+    if (err != nil) {
+        log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+        return elements, nil, err
+    }
+    for _, e := range awsoutput.TagList {
+        elements = append(elements, e)
+    }
+    log.DefaultLogger.Debug("workspaces.DescribeTags Elements.", "cache_length", len(elements))
+    return elements, nil, nil
+}
+
+func (samm *SammTag) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,179 @@
+package samm
+
+import (
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammUser struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeUsersInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammUser(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammUser {
+    return SammUser{
+        attributes: map[string]interface{} {
+            "Arn": []*string{},
+            "AuthenticationType": []*string{},
+            "CreatedTime": []*time.Time{},
+            "Enabled": []*bool{},
+            "FirstName": []*string{},
+            "LastName": []*string{},
+            "Status": []*string{},
+            "UserName": []*string{},
+        },
+        defaultFieldList: []string {
+            "UserName",
+            "FirstName",
+            "LastName",
+            "AuthenticationType",
+            "Enabled",
+            "Status",
+            "CreatedTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammUser) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.User)
+    switch name {
+    case "Arn":
+        field.Append(object.Arn)
+    case "AuthenticationType":
+        field.Append(object.AuthenticationType)
+    case "CreatedTime":
+        field.Append(object.CreatedTime)
+    case "Enabled":
+        field.Append(object.Enabled)
+    case "FirstName":
+        field.Append(object.FirstName)
+    case "LastName":
+        field.Append(object.LastName)
+    case "Status":
+        field.Append(object.Status)
+    case "UserName":
+        field.Append(object.UserName)
+    }
+}
+
+func (samm SammUser) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammUser) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammUser) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammUser) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeUsersInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    /* AuthenticationType is mandatory for DescribeUsers */
+    samm.filter.SetAuthenticationType(appstream.AuthenticationTypeUserpool)
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "AuthenticationType")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "AuthenticationType":
+            samm.filter.SetAuthenticationType(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammUser) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammUser) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammUser) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammUser) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammUser) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeUsersOutput
+
+        awsoutput, err = samm.svc.DescribeUsers(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Users {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeUsers Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammUser) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
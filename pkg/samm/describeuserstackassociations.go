@@ -0,0 +1,173 @@
+package samm
+
+import (
+    "errors"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammUserStackAssociation struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.DescribeUserStackAssociationsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammUserStackAssociation(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammUserStackAssociation {
+    return SammUserStackAssociation{
+        attributes: map[string]interface{} {
+            "AuthenticationType": []*string{},
+            "SendEmailNotification": []*bool{},
+            "StackName": []*string{},
+            "UserName": []*string{},
+        },
+        defaultFieldList: []string {
+            "UserName",
+            "StackName",
+            "AuthenticationType",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammUserStackAssociation) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.UserStackAssociation)
+    switch name {
+    case "AuthenticationType":
+        field.Append(object.AuthenticationType)
+    case "SendEmailNotification":
+        field.Append(object.SendEmailNotification)
+    case "StackName":
+        field.Append(object.StackName)
+    case "UserName":
+        field.Append(object.UserName)
+    }
+}
+
+func (samm SammUserStackAssociation) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammUserStackAssociation) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammUserStackAssociation) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammUserStackAssociation) createFilter(NextToken *string) {
+    samm.filter = &appstream.DescribeUserStackAssociationsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "AuthenticationType", "StackName", "UserName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "AuthenticationType":
+            samm.filter.SetAuthenticationType(filterCondition.Value)
+        case "StackName":
+            samm.filter.SetStackName(filterCondition.Value)
+        case "UserName":
+            samm.filter.SetUserName(filterCondition.Value)
+        }
+    }
+    /* AuthenticationType is required when filtering by UserName */
+    if samm.filter.UserName != nil && samm.filter.AuthenticationType == nil {
+        samm.filter.SetAuthenticationType(appstream.AuthenticationTypeUserpool)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammUserStackAssociation) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammUserStackAssociation) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammUserStackAssociation) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammUserStackAssociation) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammUserStackAssociation) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.DescribeUserStackAssociationsOutput
+
+        awsoutput, err = samm.svc.DescribeUserStackAssociations(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.UserStackAssociations {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.DescribeUserStackAssociations Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammUserStackAssociation) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+    /* StackName or UserName is mandatory for DescribeUserStackAssociations */
+    if samm.filter.StackName == nil && samm.filter.UserName == nil {
+        return errors.New("StackName or UserName is mandatory.")
+    }
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
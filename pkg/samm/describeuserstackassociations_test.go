@@ -0,0 +1,22 @@
+package samm
+
+import (
+	"testing"
+
+	"github.com/samana-group/sammaws/pkg/models"
+)
+
+func TestUserStackAssociationsMandatoryFilter(t *testing.T) {
+	tests := [][]models.FilterCondition{
+		nil,
+		{{Property: "AuthenticationType", Value: "USERPOOL"}},
+		{{Property: "StackName", Operator: "!=", Value: "stack-1"}},
+	}
+	for _, filterConditions := range tests {
+		/* svc is nil: the query must fail before any AWS call */
+		su := NewSammUserStackAssociation(nil, filterConditions, 0)
+		if err := su.UpdateElements([]interface{}{}, nil, false); err == nil {
+			t.Errorf("%v: expected StackName or UserName to be mandatory", filterConditions)
+		}
+	}
+}
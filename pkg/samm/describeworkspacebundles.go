@@ -1,7 +1,6 @@
 package samm
 
 import (
-	"fmt"
 	"time"
 	"encoding/json"
 
@@ -16,17 +15,19 @@ import (
 
 type SammWorkspaceBundle struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter *workspaces.DescribeWorkspaceBundlesInput
     filterConditions []models.FilterCondition
     limit int
+    nestedFieldList []string
     nextToken *string
     svc *workspaces.WorkSpaces
 }
 
 func NewSammWorkspaceBundle(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspaceBundle {
-    return SammWorkspaceBundle{
+    samm := SammWorkspaceBundle{
         attributes: map[string]interface{}{
             "BundleId": []*string{},
             "BundleType": []*string{},
@@ -56,6 +57,8 @@ func NewSammWorkspaceBundle(svc *workspaces.WorkSpaces, filterConditions []model
         limit: Limit,
         svc: svc,
     }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &workspaces.WorkspaceBundle{}, "ComputeType", "RootStorage", "UserStorage")
+    return samm
 }
 
 func (samm SammWorkspaceBundle) AppendData(elementIndex int, field *data.Field, name string) {
@@ -88,6 +91,10 @@ func (samm SammWorkspaceBundle) AppendData(elementIndex int, field *data.Field,
     case "UserStorage":
         temp, _ := json.Marshal(object.UserStorage)
         field.Append(string(temp))
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
     }
 }
 
@@ -103,6 +110,10 @@ func (samm SammWorkspaceBundle) AttributeType(attributeName string) (interface{}
     return attr, ok
 }
 
+func (samm SammWorkspaceBundle) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammWorkspaceBundle) createFilter(NextToken *string) {
     samm.filter = &workspaces.DescribeWorkspaceBundlesInput{}
 
@@ -110,16 +121,17 @@ func (samm *SammWorkspaceBundle) createFilter(NextToken *string) {
         samm.filter.SetNextToken(*NextToken)
     }
     bundleIds := []*string{}
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "BundleId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "BundleId":
-            bundleIds = append(bundleIds, &filterCondition.Value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
+            value := filterCondition.Value
+            bundleIds = append(bundleIds, &value)
         }
     }
     if len(bundleIds) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by BundleIds.", "ids_count", len(bundleIds)))
+        log.DefaultLogger.Debug("Filter by BundleIds.", "ids_count", len(bundleIds))
         samm.filter.SetBundleIds(bundleIds)
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -137,6 +149,10 @@ func (samm SammWorkspaceBundle) Len() int {
     return len(samm.elements)
 }
 
+func (samm SammWorkspaceBundle) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
 func (samm SammWorkspaceBundle) NextToken() *string {
     return samm.nextToken
 }
@@ -168,7 +184,6 @@ func (samm *SammWorkspaceBundle) Query(elements []interface{}) ([]interface{}, *
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammWorkspaceBundle) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -179,6 +194,7 @@ func (samm *SammWorkspaceBundle) UpdateElements(cachedElements interface{}, next
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
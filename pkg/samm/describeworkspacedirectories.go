@@ -2,12 +2,12 @@ package samm
 
 import (
 	"strings"
-	"fmt"
 	"strconv"
 	"encoding/json"
 
 	"github.com/samana-group/sammaws/pkg/models"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/workspaces"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -17,17 +17,20 @@ import (
 
 type SammWorkspaceDirectory struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter *workspaces.DescribeWorkspaceDirectoriesInput
     filterConditions []models.FilterCondition
     limit int
+    nestedFieldList []string
     nextToken *string
     svc *workspaces.WorkSpaces
+    tags ResourceTags
 }
 
 func NewSammWorkspacesDirectory(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspaceDirectory {
-    return SammWorkspaceDirectory{
+    samm := SammWorkspaceDirectory{
         attributes: map[string]interface{} {
             "ActiveDirectoryConfig": []string{},
             "Alias": []*string{},
@@ -70,6 +73,8 @@ func NewSammWorkspacesDirectory(svc *workspaces.WorkSpaces, filterConditions []m
         limit: Limit,
         svc: svc,
     }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &workspaces.WorkspaceDirectory{}, "ActiveDirectoryConfig", "CertificateBasedAuthProperties", "SamlProperties", "SelfservicePermissions", "StreamingProperties", "WorkspaceAccessProperties", "WorkspaceCreationProperties")
+    return samm
 }
 
 func (samm SammWorkspaceDirectory) AppendData(elementIndex int, field *data.Field, name string) {
@@ -146,6 +151,12 @@ func (samm SammWorkspaceDirectory) AppendData(elementIndex int, field *data.Fiel
         field.Append(object.WorkspaceSecurityGroupId)
     case "WorkspaceType":
         field.Append(object.WorkspaceType)
+    default:
+        if IsTagField(name) {
+            field.Append(samm.tags.Value(object.DirectoryId, TagKey(name)))
+        } else if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
     }
 }
 
@@ -157,10 +168,17 @@ func (samm SammWorkspaceDirectory) At(index int) interface{} {
 }
 
 func (samm SammWorkspaceDirectory) AttributeType(attributeName string) (interface{}, bool) {
+    if IsTagField(attributeName) {
+        return []*string{}, true
+    }
     attr, ok := samm.attributes[attributeName]
     return attr, ok
 }
 
+func (samm SammWorkspaceDirectory) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammWorkspaceDirectory) createFilter(NextToken *string) {
     samm.filter = &workspaces.DescribeWorkspaceDirectoriesInput{}
 
@@ -169,22 +187,24 @@ func (samm *SammWorkspaceDirectory) createFilter(NextToken *string) {
     }
     directoryIds := []*string{}
     directoryNames := []*string{}
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "DirectoryId", "DirectoryName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "DirectoryId":
-                directoryIds = append(directoryIds, &filterCondition.Value)
+            value := filterCondition.Value
+            directoryIds = append(directoryIds, &value)
         case "DirectoryName":
-                directoryNames = append(directoryNames, &filterCondition.Value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
+            value := filterCondition.Value
+            directoryNames = append(directoryNames, &value)
         }
     }
     if len(directoryIds) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by DirectoryIds.", "ids_count", len(directoryIds)))
+        log.DefaultLogger.Debug("Filter by DirectoryIds.", "ids_count", len(directoryIds))
         samm.filter.SetDirectoryIds(directoryIds)
     }
-    if len(directoryIds) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by WorkspaceDirectoryNames.", "names_count", len(directoryNames)))
+    if len(directoryNames) > 0 {
+        log.DefaultLogger.Debug("Filter by WorkspaceDirectoryNames.", "names_count", len(directoryNames))
         samm.filter.SetWorkspaceDirectoryNames(directoryNames)
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -202,10 +222,27 @@ func (samm SammWorkspaceDirectory) Len() int {
     return len(samm.elements)
 }
 
+func (samm SammWorkspaceDirectory) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
 func (samm SammWorkspaceDirectory) NextToken() *string {
     return samm.nextToken
 }
 
+func (samm SammWorkspaceDirectory) ResourceIds() []string {
+    ids := make([]string, len(samm.elements))
+    for i, e := range samm.elements {
+        ids[i] = aws.StringValue(e.(*workspaces.WorkspaceDirectory).DirectoryId)
+    }
+    return ids
+}
+
+/* SetTags attaches the tags to the elements, tag filters are applied with the other client side filters. */
+func (samm *SammWorkspaceDirectory) SetTags(tags ResourceTags) {
+    samm.tags = tags
+}
+
 func (samm *SammWorkspaceDirectory) Query(elements []interface{}) ([]interface{}, *string, error) {
     var err error
     NextToken := samm.filter.NextToken
@@ -233,7 +270,6 @@ func (samm *SammWorkspaceDirectory) Query(elements []interface{}) ([]interface{}
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammWorkspaceDirectory) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -244,6 +280,7 @@ func (samm *SammWorkspaceDirectory) UpdateElements(cachedElements interface{}, n
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
@@ -0,0 +1,162 @@
+package samm
+
+import (
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+/* Element of DescribeWorkspaceImagePermissions. */
+type SammImagePermissionItem struct {
+    ImageId *string
+    SharedAccountId *string
+}
+
+type SammWorkspaceImagePermission struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeWorkspaceImagePermissionsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammWorkspaceImagePermission(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspaceImagePermission {
+    return SammWorkspaceImagePermission{
+        attributes: map[string]interface{} {
+            "ImageId": []*string{},
+            "SharedAccountId": []*string{},
+        },
+        defaultFieldList: []string {
+            "ImageId",
+            "SharedAccountId",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammWorkspaceImagePermission) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*SammImagePermissionItem)
+    switch name {
+    case "ImageId":
+        field.Append(object.ImageId)
+    case "SharedAccountId":
+        field.Append(object.SharedAccountId)
+    }
+}
+
+func (samm SammWorkspaceImagePermission) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammWorkspaceImagePermission) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammWorkspaceImagePermission) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammWorkspaceImagePermission) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeWorkspaceImagePermissionsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    /* ImageId is mandatory for DescribeWorkspaceImagePermissions */
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "ImageId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "ImageId":
+            samm.filter.SetImageId(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammWorkspaceImagePermission) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammWorkspaceImagePermission) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammWorkspaceImagePermission) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammWorkspaceImagePermission) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammWorkspaceImagePermission) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeWorkspaceImagePermissionsOutput
+
+        awsoutput, err = samm.svc.DescribeWorkspaceImagePermissions(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.ImagePermissions {
+            elements = append(elements, &SammImagePermissionItem{
+                ImageId: awsoutput.ImageId,
+                SharedAccountId: e.SharedAccountId,
+            })
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeWorkspaceImagePermissions Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammWorkspaceImagePermission) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,224 @@
+package samm
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammWorkspaceImage struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeWorkspaceImagesInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammWorkspaceImage(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspaceImage {
+    samm := SammWorkspaceImage{
+        attributes: map[string]interface{} {
+            "Created": []*time.Time{},
+            "Description": []*string{},
+            "ErrorCode": []*string{},
+            "ErrorDetails": []string{},
+            "ErrorMessage": []*string{},
+            "ImageId": []*string{},
+            "Name": []*string{},
+            "OperatingSystem": []string{},
+            "OwnerAccountId": []*string{},
+            "RequiredTenancy": []*string{},
+            "State": []*string{},
+            "Updates": []string{},
+        },
+        defaultFieldList: []string {
+            "ImageId",
+            "Name",
+            "Description",
+            "State",
+            "RequiredTenancy",
+            "OwnerAccountId",
+            "Created",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &workspaces.WorkspaceImage{}, "ErrorDetails", "OperatingSystem", "Updates")
+    return samm
+}
+
+func (samm SammWorkspaceImage) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*workspaces.WorkspaceImage)
+    switch name {
+    case "Created":
+        field.Append(object.Created)
+    case "Description":
+        field.Append(object.Description)
+    case "ErrorCode":
+        field.Append(object.ErrorCode)
+    case "ErrorDetails":
+        temp, err := json.Marshal(object.ErrorDetails)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "ErrorMessage":
+        field.Append(object.ErrorMessage)
+    case "ImageId":
+        field.Append(object.ImageId)
+    case "Name":
+        field.Append(object.Name)
+    case "OperatingSystem":
+        temp, err := json.Marshal(object.OperatingSystem)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "OwnerAccountId":
+        field.Append(object.OwnerAccountId)
+    case "RequiredTenancy":
+        field.Append(object.RequiredTenancy)
+    case "State":
+        field.Append(object.State)
+    case "Updates":
+        temp, err := json.Marshal(object.Updates)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammWorkspaceImage) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammWorkspaceImage) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammWorkspaceImage) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammWorkspaceImage) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeWorkspaceImagesInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    imageIds := []*string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "ImageId", "ImageType")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "ImageId":
+            imageIds = append(imageIds, aws.String(filterCondition.Value))
+        case "ImageType":
+            samm.filter.SetImageType(filterCondition.Value)
+        }
+    }
+    if len(imageIds) > 0 {
+        log.DefaultLogger.Debug("Filter by imageIds.", "imageIds_count", len(imageIds))
+        samm.filter.SetImageIds(imageIds)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammWorkspaceImage) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammWorkspaceImage) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammWorkspaceImage) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammWorkspaceImage) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammWorkspaceImage) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammWorkspaceImage) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeWorkspaceImagesOutput
+
+        awsoutput, err = samm.svc.DescribeWorkspaceImages(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Images {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeWorkspaceImages Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammWorkspaceImage) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
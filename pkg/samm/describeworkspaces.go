@@ -2,10 +2,11 @@ package samm
 
 import (
 	"strings"
-	"fmt"
 
 	"github.com/samana-group/sammaws/pkg/models"
+	"github.com/samana-group/sammaws/pkg/pricing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/workspaces"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -15,26 +16,34 @@ import (
 
 type SammWorkspace struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter *workspaces.DescribeWorkspacesInput
     filterConditions []models.FilterCondition
     limit int
+    nestedFieldList []string
     nextToken *string
+    pricing *pricing.Table
+    region string
     svc *workspaces.WorkSpaces
+    tags ResourceTags
 }
 
 func NewSammWorkspace(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspace {
-    return SammWorkspace{
+    samm := SammWorkspace{
         attributes: map[string]interface{} {
             "BundleId":  []*string{},
             "ComputerName": []*string{},
             "DataReplicationSettings":  []string{},
             "DirectoryId": []*string{},
             "ErrorCode": []*string{},
+            "EstimatedMonthlyCost": []*float64{},
+            "HourlyRate": []*float64{},
             "ErrorMessage": []*string{},
             "IpAddress": []*string{},
             "ModificationStates":  []string{},
+            "MonthlyRate": []*float64{},
             "RelatedWorkspaces":  []string{},
             "RootVolumeEncryptionEnabled": []*bool{},
             "StandbyWorkspacesProperties":  []string{},
@@ -63,6 +72,8 @@ func NewSammWorkspace(svc *workspaces.WorkSpaces, filterConditions []models.Filt
         limit: Limit,
         svc: svc,
     }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &workspaces.Workspace{}, "DataReplicationSettings", "ModificationStates", "RelatedWorkspaces", "StandbyWorkspacesProperties", "WorkspaceProperties")
+    return samm
 }
 
 func (samm SammWorkspace) AppendData(elementIndex int, field *data.Field, name string) {
@@ -80,6 +91,18 @@ func (samm SammWorkspace) AppendData(elementIndex int, field *data.Field, name s
         field.Append(object.ErrorCode)
     case "ErrorMessage":
         field.Append(object.ErrorMessage)
+    case "EstimatedMonthlyCost":
+        if price := samm.price(object); price != nil {
+            field.Append(aws.Float64(samm.pricing.MonthlyCost(price, pricingCriteria(object).RunningMode)))
+        } else {
+            field.Append((*float64)(nil))
+        }
+    case "HourlyRate":
+        if price := samm.price(object); price != nil {
+            field.Append(aws.Float64(price.Hourly))
+        } else {
+            field.Append((*float64)(nil))
+        }
     case "IpAddress":
         field.Append(object.IpAddress)
     case "ModificationStates":
@@ -88,6 +111,12 @@ func (samm SammWorkspace) AppendData(elementIndex int, field *data.Field, name s
             temp[i] = state.String()
         }
         field.Append(strings.Join(temp, ","))
+    case "MonthlyRate":
+        if price := samm.price(object); price != nil {
+            field.Append(aws.Float64(price.Monthly))
+        } else {
+            field.Append((*float64)(nil))
+        }
     case "RelatedWorkspaces":
         temp := make([]string, len(object.RelatedWorkspaces))
         for i, state := range object.RelatedWorkspaces {
@@ -118,9 +147,39 @@ func (samm SammWorkspace) AppendData(elementIndex int, field *data.Field, name s
         field.Append(object.WorkspaceName)
     case "WorkspaceProperties":
         field.Append(object.WorkspaceProperties.String())
+    default:
+        if IsTagField(name) {
+            field.Append(samm.tags.Value(object.WorkspaceId, TagKey(name)))
+        } else if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
     }
 }
 
+/* pricingCriteria returns the properties of a workspace used to find its price */
+type workspacePricing struct {
+    ComputeType string
+    RunningMode string
+    OperatingSystem string
+}
+
+func pricingCriteria(object *workspaces.Workspace) workspacePricing {
+    properties := object.WorkspaceProperties
+    if properties == nil {
+        return workspacePricing{}
+    }
+    return workspacePricing{
+        ComputeType: aws.StringValue(properties.ComputeTypeName),
+        RunningMode: aws.StringValue(properties.RunningMode),
+        OperatingSystem: aws.StringValue(properties.OperatingSystemName),
+    }
+}
+
+func (samm SammWorkspace) price(object *workspaces.Workspace) *pricing.Price {
+    property := pricingCriteria(object)
+    return samm.pricing.Lookup(property.ComputeType, property.RunningMode, property.OperatingSystem, samm.region)
+}
+
 func (samm SammWorkspace) At(index int) interface{} {
     if index >= 0 && index < len(samm.elements) {
         return samm.elements[index]
@@ -129,10 +188,17 @@ func (samm SammWorkspace) At(index int) interface{} {
 }
 
 func (samm SammWorkspace) AttributeType(attributeName string) (interface{}, bool) {
+    if IsTagField(attributeName) {
+        return []*string{}, true
+    }
     attr, ok := samm.attributes[attributeName]
     return attr, ok
 }
 
+func (samm SammWorkspace) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammWorkspace) createFilter(NextToken *string) {
     samm.filter = &workspaces.DescribeWorkspacesInput{}
 
@@ -140,8 +206,10 @@ func (samm *SammWorkspace) createFilter(NextToken *string) {
         samm.filter.SetNextToken(*NextToken)
     }
     workspaceIds := []*string{}
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "BundleId", "DirectoryId", "UserName", "WorkspaceName", "WorkspaceId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "BundleId":
             samm.filter.SetBundleId(filterCondition.Value)
         case "DirectoryId":
@@ -153,12 +221,10 @@ func (samm *SammWorkspace) createFilter(NextToken *string) {
         case "WorkspaceId":
             value := filterCondition.Value
             workspaceIds = append(workspaceIds, &value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
         }
     }
     if len(workspaceIds) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by workspaceId.", "ids_count", len(workspaceIds)))
+        log.DefaultLogger.Debug("Filter by workspaceId.", "ids_count", len(workspaceIds))
         samm.filter.SetWorkspaceIds(workspaceIds)
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -176,10 +242,34 @@ func (samm SammWorkspace) Len() int {
     return len(samm.elements)
 }
 
+func (samm SammWorkspace) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
 func (samm SammWorkspace) NextToken() *string {
     return samm.nextToken
 }
 
+func (samm SammWorkspace) ResourceIds() []string {
+    ids := make([]string, len(samm.elements))
+    for i, e := range samm.elements {
+        ids[i] = aws.StringValue(e.(*workspaces.Workspace).WorkspaceId)
+    }
+    return ids
+}
+
+/* SetPricing adds the estimated cost fields, computed from the pricing
+ * table for the region of the workspaces. */
+func (samm *SammWorkspace) SetPricing(table *pricing.Table, region string) {
+    samm.pricing = table
+    samm.region = region
+}
+
+/* SetTags attaches the tags to the elements, tag filters are applied with the other client side filters. */
+func (samm *SammWorkspace) SetTags(tags ResourceTags) {
+    samm.tags = tags
+}
+
 func (samm *SammWorkspace) Query(elements []interface{}) ([]interface{}, *string, error) {
     var err error
     NextToken := samm.filter.NextToken
@@ -207,7 +297,6 @@ func (samm *SammWorkspace) Query(elements []interface{}) ([]interface{}, *string
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammWorkspace) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -218,6 +307,7 @@ func (samm *SammWorkspace) UpdateElements(cachedElements interface{}, nextToken
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
@@ -2,7 +2,6 @@ package samm
 
 import (
 	"time"
-	"fmt"
 	"strconv"
 
 	"github.com/samana-group/sammaws/pkg/models"
@@ -16,6 +15,7 @@ import (
 
 type SammWorkspacesConnectionStatus struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter *workspaces.DescribeWorkspacesConnectionStatusInput
@@ -71,24 +71,28 @@ func (samm SammWorkspacesConnectionStatus) AttributeType(attributeName string) (
     return attr, ok
 }
 
+func (samm SammWorkspacesConnectionStatus) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammWorkspacesConnectionStatus) createFilter(NextToken *string) {
     samm.filter = &workspaces.DescribeWorkspacesConnectionStatusInput{}
 
     if NextToken != nil {
         samm.filter.SetNextToken(*NextToken)
     }
-	workspaceIds := []*string{}
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    workspaceIds := []*string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "WorkspaceId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "WorkspaceId":
             value := filterCondition.Value
             workspaceIds = append(workspaceIds, &value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
-		}
+        }
     }
     if len(workspaceIds) > 0 {
-        log.DefaultLogger.Debug(fmt.Sprintf("Filter by workspaceId.", "ids_count", len(workspaceIds)))
+        log.DefaultLogger.Debug("Filter by workspaceId.", "ids_count", len(workspaceIds))
         samm.filter.SetWorkspaceIds(workspaceIds)
     } 
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -137,7 +141,6 @@ func (samm *SammWorkspacesConnectionStatus) Query(elements []interface{}) ([]int
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammWorkspacesConnectionStatus) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -148,6 +151,7 @@ func (samm *SammWorkspacesConnectionStatus) UpdateElements(cachedElements interf
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
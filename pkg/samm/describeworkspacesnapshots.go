@@ -0,0 +1,163 @@
+package samm
+
+import (
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+/* Element of DescribeWorkspaceSnapshots. SnapshotType is RESTORE or REBUILD. */
+type SammSnapshotItem struct {
+    WorkspaceId *string
+    SnapshotType *string
+    SnapshotTime *time.Time
+}
+
+type SammWorkspaceSnapshot struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+    workspaceIds []string
+}
+
+func NewSammWorkspaceSnapshot(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspaceSnapshot {
+    return SammWorkspaceSnapshot{
+        attributes: map[string]interface{} {
+            "SnapshotTime": []*time.Time{},
+            "SnapshotType": []*string{},
+            "WorkspaceId": []*string{},
+        },
+        defaultFieldList: []string {
+            "WorkspaceId",
+            "SnapshotType",
+            "SnapshotTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammWorkspaceSnapshot) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*SammSnapshotItem)
+    switch name {
+    case "SnapshotTime":
+        field.Append(object.SnapshotTime)
+    case "SnapshotType":
+        field.Append(object.SnapshotType)
+    case "WorkspaceId":
+        field.Append(object.WorkspaceId)
+    }
+}
+
+func (samm SammWorkspaceSnapshot) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammWorkspaceSnapshot) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+/* DescribeWorkspaceSnapshots works on a single workspace. One call is
+ * done for each WorkspaceId in the filter conditions. */
+func (samm SammWorkspaceSnapshot) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammWorkspaceSnapshot) createFilter(NextToken *string) {
+    samm.workspaceIds = []string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "WorkspaceId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "WorkspaceId":
+            samm.workspaceIds = append(samm.workspaceIds, filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "workspaceIds", samm.workspaceIds)
+}
+
+func (samm SammWorkspaceSnapshot) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammWorkspaceSnapshot) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammWorkspaceSnapshot) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammWorkspaceSnapshot) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammWorkspaceSnapshot) Query(elements []interface{}) ([]interface{}, *string, error) {
+    for _, workspaceId := range samm.workspaceIds {
+        awsoutput, err := samm.svc.DescribeWorkspaceSnapshots(&workspaces.DescribeWorkspaceSnapshotsInput{
+            WorkspaceId: aws.String(workspaceId),
+        })
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, nil, err
+        }
+        for _, e := range awsoutput.RestoreSnapshots {
+            elements = append(elements, &SammSnapshotItem{
+                WorkspaceId: aws.String(workspaceId),
+                SnapshotType: aws.String("RESTORE"),
+                SnapshotTime: e.SnapshotTime,
+            })
+        }
+        for _, e := range awsoutput.RebuildSnapshots {
+            elements = append(elements, &SammSnapshotItem{
+                WorkspaceId: aws.String(workspaceId),
+                SnapshotType: aws.String("REBUILD"),
+                SnapshotTime: e.SnapshotTime,
+            })
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeWorkspaceSnapshots Elements.", "cache_length", len(elements))
+    }
+    return elements, nil, nil
+}
+
+func (samm *SammWorkspaceSnapshot) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,247 @@
+package samm
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammWorkspacesPool struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeWorkspacesPoolsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nestedFieldList []string
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammWorkspacesPool(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspacesPool {
+    samm := SammWorkspacesPool{
+        attributes: map[string]interface{} {
+            "ApplicationSettings": []string{},
+            "BundleId": []*string{},
+            "CapacityStatus.ActiveUserSessions": []*int64{},
+            "CapacityStatus.ActualUserSessions": []*int64{},
+            "CapacityStatus.AvailableUserSessions": []*int64{},
+            "CapacityStatus.DesiredUserSessions": []*int64{},
+            "CreatedAt": []*time.Time{},
+            "Description": []*string{},
+            "DirectoryId": []*string{},
+            "Errors": []string{},
+            "PoolArn": []*string{},
+            "PoolId": []*string{},
+            "PoolName": []*string{},
+            "State": []*string{},
+            "TimeoutSettings.DisconnectTimeoutInSeconds": []*int64{},
+            "TimeoutSettings.IdleDisconnectTimeoutInSeconds": []*int64{},
+            "TimeoutSettings.MaxUserDurationInSeconds": []*int64{},
+        },
+        defaultFieldList: []string {
+            "PoolId",
+            "PoolName",
+            "State",
+            "BundleId",
+            "DirectoryId",
+            "CapacityStatus.DesiredUserSessions",
+            "CapacityStatus.ActualUserSessions",
+            "CapacityStatus.ActiveUserSessions",
+            "CapacityStatus.AvailableUserSessions",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+    samm.nestedFieldList = addNestedAttributes(samm.attributes, &workspaces.WorkspacesPool{}, "ApplicationSettings", "Errors")
+    return samm
+}
+
+func (samm SammWorkspacesPool) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*workspaces.WorkspacesPool)
+    switch name {
+    case "ApplicationSettings":
+        temp, err := json.Marshal(object.ApplicationSettings)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "BundleId":
+        field.Append(object.BundleId)
+    case "CapacityStatus.ActiveUserSessions":
+        appendNested(field, object, name)
+    case "CapacityStatus.ActualUserSessions":
+        appendNested(field, object, name)
+    case "CapacityStatus.AvailableUserSessions":
+        appendNested(field, object, name)
+    case "CapacityStatus.DesiredUserSessions":
+        appendNested(field, object, name)
+    case "CreatedAt":
+        field.Append(object.CreatedAt)
+    case "Description":
+        field.Append(object.Description)
+    case "DirectoryId":
+        field.Append(object.DirectoryId)
+    case "Errors":
+        temp, err := json.Marshal(object.Errors)
+        if err != nil {
+            log.DefaultLogger.Warn("Unable to convert to json.", "error", err.Error(), "attribute", name)
+            return
+        }
+        field.Append(string(temp))
+    case "PoolArn":
+        field.Append(object.PoolArn)
+    case "PoolId":
+        field.Append(object.PoolId)
+    case "PoolName":
+        field.Append(object.PoolName)
+    case "State":
+        field.Append(object.State)
+    case "TimeoutSettings.DisconnectTimeoutInSeconds":
+        appendNested(field, object, name)
+    case "TimeoutSettings.IdleDisconnectTimeoutInSeconds":
+        appendNested(field, object, name)
+    case "TimeoutSettings.MaxUserDurationInSeconds":
+        appendNested(field, object, name)
+    default:
+        if IsNestedField(name) {
+            appendNested(field, object, name)
+        }
+    }
+}
+
+func (samm SammWorkspacesPool) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammWorkspacesPool) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammWorkspacesPool) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammWorkspacesPool) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeWorkspacesPoolsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    poolIds := []*string{}
+    poolNames := []*string{}
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "PoolId", "PoolName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "PoolId":
+            poolIds = append(poolIds, aws.String(filterCondition.Value))
+        case "PoolName":
+            poolNames = append(poolNames, aws.String(filterCondition.Value))
+        }
+    }
+    if len(poolIds) > 0 {
+        log.DefaultLogger.Debug("Filter by PoolIds.", "ids_count", len(poolIds))
+        samm.filter.SetPoolIds(poolIds)
+    }
+    if len(poolNames) > 0 {
+        log.DefaultLogger.Debug("Filter by PoolNames.", "names_count", len(poolNames))
+        samm.filter.SetFilters([]*workspaces.DescribeWorkspacesPoolsFilter{
+            {
+                Name: aws.String(workspaces.DescribeWorkspacesPoolsFilterNamePoolName),
+                Operator: aws.String(workspaces.DescribeWorkspacesPoolsFilterOperatorEquals),
+                Values: poolNames,
+            },
+        })
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammWorkspacesPool) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammWorkspacesPool) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammWorkspacesPool) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammWorkspacesPool) NestedFieldList() []string {
+    return samm.nestedFieldList
+}
+
+func (samm SammWorkspacesPool) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammWorkspacesPool) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeWorkspacesPoolsOutput
+
+        awsoutput, err = samm.svc.DescribeWorkspacesPools(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.WorkspacesPools {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeWorkspacesPools Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammWorkspacesPool) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,187 @@
+package samm
+
+import (
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammWorkspacesPoolSession struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *workspaces.DescribeWorkspacesPoolSessionsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *workspaces.WorkSpaces
+}
+
+func NewSammWorkspacesPoolSession(svc *workspaces.WorkSpaces, filterConditions []models.FilterCondition, Limit int) SammWorkspacesPoolSession {
+    return SammWorkspacesPoolSession{
+        attributes: map[string]interface{} {
+            "AuthenticationType": []*string{},
+            "ConnectionState": []*string{},
+            "ExpirationTime": []*time.Time{},
+            "InstanceId": []*string{},
+            "NetworkAccessConfiguration.EniId": []*string{},
+            "NetworkAccessConfiguration.EniPrivateIpAddress": []*string{},
+            "PoolId": []*string{},
+            "SessionId": []*string{},
+            "StartTime": []*time.Time{},
+            "UserId": []*string{},
+        },
+        defaultFieldList: []string {
+            "SessionId",
+            "PoolId",
+            "UserId",
+            "ConnectionState",
+            "AuthenticationType",
+            "InstanceId",
+            "StartTime",
+            "ExpirationTime",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammWorkspacesPoolSession) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*workspaces.WorkspacesPoolSession)
+    switch name {
+    case "AuthenticationType":
+        field.Append(object.AuthenticationType)
+    case "ConnectionState":
+        field.Append(object.ConnectionState)
+    case "ExpirationTime":
+        field.Append(object.ExpirationTime)
+    case "InstanceId":
+        field.Append(object.InstanceId)
+    case "NetworkAccessConfiguration.EniId":
+        appendNested(field, object, name)
+    case "NetworkAccessConfiguration.EniPrivateIpAddress":
+        appendNested(field, object, name)
+    case "PoolId":
+        field.Append(object.PoolId)
+    case "SessionId":
+        field.Append(object.SessionId)
+    case "StartTime":
+        field.Append(object.StartTime)
+    case "UserId":
+        field.Append(object.UserId)
+    }
+}
+
+func (samm SammWorkspacesPoolSession) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammWorkspacesPoolSession) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammWorkspacesPoolSession) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammWorkspacesPoolSession) createFilter(NextToken *string) {
+    samm.filter = &workspaces.DescribeWorkspacesPoolSessionsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    /* PoolId is mandatory for DescribeWorkspacesPoolSessions */
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "PoolId", "UserId")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "PoolId":
+            samm.filter.SetPoolId(filterCondition.Value)
+        case "UserId":
+            samm.filter.SetUserId(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammWorkspacesPoolSession) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammWorkspacesPoolSession) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammWorkspacesPoolSession) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammWorkspacesPoolSession) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammWorkspacesPoolSession) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *workspaces.DescribeWorkspacesPoolSessionsOutput
+
+        awsoutput, err = samm.svc.DescribeWorkspacesPoolSessions(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Sessions {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("workspaces.DescribeWorkspacesPoolSessions Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammWorkspacesPoolSession) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
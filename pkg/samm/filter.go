@@ -0,0 +1,293 @@
+package samm
+
+import (
+    "fmt"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+/* Filter conditions AWS cannot apply are evaluated on the collected
+ * elements. Values are compared as numbers, booleans or times when the
+ * field has that type, otherwise as strings. Times accept RFC3339, a date
+ * (2006-01-02) or a time relative to now such as "now-30d" or "now+2h". */
+type filterMatcher struct {
+    condition models.FilterCondition
+    operator string
+    regex *regexp.Regexp
+    values []string
+}
+
+func newFilterMatcher(condition models.FilterCondition) (filterMatcher, error) {
+    matcher := filterMatcher{
+        condition: condition,
+        operator: strings.ToLower(strings.TrimSpace(condition.Operator)),
+    }
+    switch matcher.operator {
+    case "":
+        matcher.operator = "="
+    case "=", "!=", "contains", "<", ">", "<=", ">=", "is empty", "is not empty":
+    case "in", "not in":
+        for _, value := range strings.Split(condition.Value, ",") {
+            matcher.values = append(matcher.values, strings.TrimSpace(value))
+        }
+    case "regex":
+        regex, err := regexp.Compile(condition.Value)
+        if err != nil {
+            return matcher, fmt.Errorf("Invalid regex for property %s: %s", condition.Property, err.Error())
+        }
+        matcher.regex = regex
+    default:
+        return matcher, fmt.Errorf("Invalid operator '%s' for property %s.", condition.Operator, condition.Property)
+    }
+    return matcher, nil
+}
+
+func (m filterMatcher) match(value interface{}) bool {
+    switch m.operator {
+    case "=":
+        return m.equal(value, m.condition.Value)
+    case "!=":
+        return !m.equal(value, m.condition.Value)
+    case "in":
+        for _, operand := range m.values {
+            if m.equal(value, operand) {
+                return true
+            }
+        }
+        return false
+    case "not in":
+        for _, operand := range m.values {
+            if m.equal(value, operand) {
+                return false
+            }
+        }
+        return true
+    case "contains":
+        return value != nil && strings.Contains(ValueString(value), m.condition.Value)
+    case "regex":
+        return value != nil && m.regex.MatchString(ValueString(value))
+    case "<":
+        c, ok := filterCompare(value, m.condition.Value)
+        return ok && c < 0
+    case ">":
+        c, ok := filterCompare(value, m.condition.Value)
+        return ok && c > 0
+    case "<=":
+        c, ok := filterCompare(value, m.condition.Value)
+        return ok && c <= 0
+    case ">=":
+        c, ok := filterCompare(value, m.condition.Value)
+        return ok && c >= 0
+    case "is empty":
+        return value == nil || ValueString(value) == ""
+    case "is not empty":
+        return value != nil && ValueString(value) != ""
+    }
+    return false
+}
+
+func (m filterMatcher) equal(value interface{}, operand string) bool {
+    c, ok := filterCompare(value, operand)
+    return ok && c == 0
+}
+
+/* splitConditions returns the equal conditions on the properties the AWS
+ * API filters on, and the other conditions, left to FilterElements. */
+func splitConditions(filterConditions []models.FilterCondition, awsProperties ...string) ([]models.FilterCondition, []models.FilterCondition) {
+    awsConditions := []models.FilterCondition{}
+    clientConditions := []models.FilterCondition{}
+    for _, filterCondition := range filterConditions {
+        isAws := false
+        if filterCondition.IsEqual() {
+            for _, property := range awsProperties {
+                if filterCondition.Property == property {
+                    isAws = true
+                    break
+                }
+            }
+        }
+        if isAws {
+            awsConditions = append(awsConditions, filterCondition)
+        } else {
+            clientConditions = append(clientConditions, filterCondition)
+        }
+    }
+    return awsConditions, clientConditions
+}
+
+/* FilterElements returns the index of the elements matching all the
+ * conditions. A condition on a property that is not a field of the
+ * elements is an error. */
+func FilterElements(elements SammElement, filterConditions []models.FilterCondition) ([]int, error) {
+    rows := make([]int, 0, elements.Len())
+    for i := 0; i < elements.Len(); i++ {
+        rows = append(rows, i)
+    }
+    for _, filterCondition := range filterConditions {
+        fieldType, ok := elements.AttributeType(filterCondition.Property)
+        if !ok {
+            return nil, fmt.Errorf("Invalid property in filter %s.", filterCondition.Property)
+        }
+        matcher, err := newFilterMatcher(filterCondition)
+        if err != nil {
+            return nil, err
+        }
+        field := data.NewField(filterCondition.Property, nil, fieldType)
+        for i := 0; i < elements.Len(); i++ {
+            elements.AppendData(i, field, filterCondition.Property)
+        }
+        matched := rows[:0]
+        for _, row := range rows {
+            if matcher.match(FieldValue(field, row)) {
+                matched = append(matched, row)
+            }
+        }
+        rows = matched
+    }
+    return rows, nil
+}
+
+/* FieldValue returns the value of a field as a string, float64, bool or
+ * time.Time, or nil when the value is not set. */
+func FieldValue(field *data.Field, index int) interface{} {
+    value, ok := field.ConcreteAt(index)
+    if !ok {
+        return nil
+    }
+    switch v := value.(type) {
+    case int:
+        return float64(v)
+    case int8:
+        return float64(v)
+    case int16:
+        return float64(v)
+    case int32:
+        return float64(v)
+    case int64:
+        return float64(v)
+    case uint8:
+        return float64(v)
+    case uint16:
+        return float64(v)
+    case uint32:
+        return float64(v)
+    case uint64:
+        return float64(v)
+    case float32:
+        return float64(v)
+    }
+    return value
+}
+
+/* ValueString formats a value returned by FieldValue. */
+func ValueString(value interface{}) string {
+    switch v := value.(type) {
+    case nil:
+        return ""
+    case string:
+        return v
+    case float64:
+        return strconv.FormatFloat(v, 'f', -1, 64)
+    case time.Time:
+        return v.Format(time.RFC3339)
+    }
+    return fmt.Sprint(value)
+}
+
+/* filterCompare compares a value with an operand and returns -1, 0 or 1.
+ * It returns false when the operand cannot be converted to the type of
+ * the value, or when the value is nil. */
+func filterCompare(value interface{}, operand string) (int, bool) {
+    switch v := value.(type) {
+    case nil:
+        return 0, false
+    case float64:
+        n, err := strconv.ParseFloat(strings.TrimSpace(operand), 64)
+        if err != nil {
+            return 0, false
+        }
+        switch {
+        case v < n:
+            return -1, true
+        case v > n:
+            return 1, true
+        }
+        return 0, true
+    case bool:
+        b, err := strconv.ParseBool(strings.TrimSpace(operand))
+        if err != nil {
+            return 0, false
+        }
+        switch {
+        case v == b:
+            return 0, true
+        case !v:
+            return -1, true
+        }
+        return 1, true
+    case time.Time:
+        t, err := ParseFilterTime(operand, time.Now())
+        if err != nil {
+            return 0, false
+        }
+        return v.Compare(t), true
+    }
+    return strings.Compare(ValueString(value), operand), true
+}
+
+/* ParseFilterTime parses an absolute time or a time relative to now such
+ * as "now", "now-7d" or "now+12h". Units are s, m, h, d and w. */
+func ParseFilterTime(value string, now time.Time) (time.Time, error) {
+    value = strings.TrimSpace(value)
+    if strings.HasPrefix(value, "now") {
+        offset := strings.TrimPrefix(value, "now")
+        if offset == "" {
+            return now, nil
+        }
+        duration, err := parseFilterDuration(offset)
+        if err != nil {
+            return now, err
+        }
+        return now.Add(duration), nil
+    }
+    if t, err := time.Parse(time.RFC3339, value); err == nil {
+        return t, nil
+    }
+    return time.Parse("2006-01-02", value)
+}
+
+func parseFilterDuration(offset string) (time.Duration, error) {
+    if len(offset) < 3 || (offset[0] != '-' && offset[0] != '+') {
+        return 0, fmt.Errorf("Invalid relative time 'now%s'.", offset)
+    }
+    unit := offset[len(offset) - 1]
+    count, err := strconv.Atoi(offset[1:len(offset) - 1])
+    if err != nil {
+        return 0, fmt.Errorf("Invalid relative time 'now%s'.", offset)
+    }
+    var duration time.Duration
+    switch unit {
+    case 's':
+        duration = time.Second
+    case 'm':
+        duration = time.Minute
+    case 'h':
+        duration = time.Hour
+    case 'd':
+        duration = 24 * time.Hour
+    case 'w':
+        duration = 7 * 24 * time.Hour
+    default:
+        return 0, fmt.Errorf("Invalid unit '%c' in relative time.", unit)
+    }
+    if offset[0] == '-' {
+        duration = -duration
+    }
+    return time.Duration(count) * duration, nil
+}
@@ -0,0 +1,261 @@
+package samm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/appstream"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+
+	"github.com/samana-group/sammaws/pkg/models"
+)
+
+/* filterWorkspaces returns the ids of the workspaces matching the
+ * conditions, the elements being loaded from a valid cache. */
+func filterWorkspaces(t *testing.T, filterConditions []models.FilterCondition) ([]string, error) {
+	t.Helper()
+	elements := []interface{}{
+		&workspaces.Workspace{
+			WorkspaceId:         aws.String("ws-1"),
+			State:               aws.String("AVAILABLE"),
+			UserName:            aws.String("alice"),
+			WorkspaceProperties: &workspaces.WorkspaceProperties{RootVolumeSizeGib: aws.Int64(80)},
+		},
+		&workspaces.Workspace{
+			WorkspaceId:         aws.String("ws-2"),
+			State:               aws.String("STOPPED"),
+			UserName:            aws.String("bob"),
+			WorkspaceProperties: &workspaces.WorkspaceProperties{RootVolumeSizeGib: aws.Int64(175)},
+		},
+		&workspaces.Workspace{
+			WorkspaceId: aws.String("ws-3"),
+			State:       aws.String("UNHEALTHY"),
+		},
+	}
+	sw := NewSammWorkspace(nil, filterConditions, 0)
+	if err := sw.UpdateElements(elements, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := FilterElements(sw, sw.ClientFilterConditions())
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, row := range rows {
+		ids = append(ids, aws.StringValue(sw.At(row).(*workspaces.Workspace).WorkspaceId))
+	}
+	return ids, nil
+}
+
+func TestFilterOperators(t *testing.T) {
+	tests := []struct {
+		property string
+		operator string
+		value    string
+		want     string
+	}{
+		{"State", "", "STOPPED", "ws-2"},
+		{"State", "=", "STOPPED", "ws-2"},
+		{"State", "!=", "STOPPED", "ws-1,ws-3"},
+		{"State", "in", "AVAILABLE, UNHEALTHY", "ws-1,ws-3"},
+		{"State", "not in", " AVAILABLE ,UNHEALTHY ", "ws-2"},
+		{"UserName", "contains", "li", "ws-1"},
+		{"UserName", "regex", "^b.b$", "ws-2"},
+		{"WorkspaceProperties.RootVolumeSizeGib", "<", "100", "ws-1"},
+		{"WorkspaceProperties.RootVolumeSizeGib", ">", "80", "ws-2"},
+		{"WorkspaceProperties.RootVolumeSizeGib", "<=", "80", "ws-1"},
+		{"WorkspaceProperties.RootVolumeSizeGib", ">=", "80", "ws-1,ws-2"},
+		{"UserName", "is empty", "", "ws-3"},
+		{"UserName", "is not empty", "", "ws-1,ws-2"},
+		{"UserName", "IS EMPTY", "", "ws-3"},
+		{"WorkspaceProperties.RootVolumeSizeGib", ">", "many", ""},
+	}
+	for _, tt := range tests {
+		condition := models.FilterCondition{Property: tt.property, Operator: tt.operator, Value: tt.value}
+		ids, err := filterWorkspaces(t, []models.FilterCondition{condition})
+		if err != nil {
+			t.Errorf("%s %s %q: %s", tt.property, tt.operator, tt.value, err)
+			continue
+		}
+		if got := strings.Join(ids, ","); got != tt.want {
+			t.Errorf("%s %s %q: got %q, want %q", tt.property, tt.operator, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	tests := []struct {
+		condition models.FilterCondition
+		want      string
+	}{
+		{models.FilterCondition{Property: "UserName", Operator: "regex", Value: "a("}, "Invalid regex"},
+		{models.FilterCondition{Property: "UserName", Operator: "like", Value: "a"}, "Invalid operator"},
+		{models.FilterCondition{Property: "Owner", Operator: "!=", Value: "a"}, "Invalid property"},
+		{models.FilterCondition{Property: "Owner", Value: "a"}, "Invalid property"},
+	}
+	for _, tt := range tests {
+		_, err := filterWorkspaces(t, []models.FilterCondition{tt.condition})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: got error %v, want %q", tt.condition, err, tt.want)
+		}
+	}
+}
+
+func TestFilterTimes(t *testing.T) {
+	now := time.Now()
+	sc := NewSammWorkspacesConnectionStatus(nil, []models.FilterCondition{
+		{Property: "LastKnownUserConnectionTimestamp", Operator: "<", Value: "now-30d"},
+	}, 0)
+	sc.UpdateElements([]interface{}{
+		&workspaces.WorkspaceConnectionStatus{WorkspaceId: aws.String("ws-1"), LastKnownUserConnectionTimestamp: aws.Time(now.Add(-40 * 24 * time.Hour))},
+		&workspaces.WorkspaceConnectionStatus{WorkspaceId: aws.String("ws-2"), LastKnownUserConnectionTimestamp: aws.Time(now.Add(-2 * time.Hour))},
+		&workspaces.WorkspaceConnectionStatus{WorkspaceId: aws.String("ws-3")},
+	}, nil, true)
+	rows, err := FilterElements(sc, sc.ClientFilterConditions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, []int{0}) {
+		t.Fatalf("expected only the workspace idle for 40 days, got rows %v", rows)
+	}
+
+	rows, err = FilterElements(sc, []models.FilterCondition{
+		{Property: "LastKnownUserConnectionTimestamp", Operator: ">", Value: now.Add(-24 * time.Hour).Format(time.RFC3339)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, []int{1}) {
+		t.Fatalf("expected only the workspace used today, got rows %v", rows)
+	}
+}
+
+func TestFilterCompare(t *testing.T) {
+	day := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   interface{}
+		operand string
+		want    int
+		ok      bool
+	}{
+		{2.5, "10", -1, true},
+		{10.0, " 10 ", 0, true},
+		{10.0, "ten", 0, false},
+		{true, "false", 1, true},
+		{false, "yes", 0, false},
+		{day, "2026-05-09", 1, true},
+		{day, "2026-05-10T00:00:00Z", 0, true},
+		{day, "someday", 0, false},
+		{"b", "a", 1, true},
+		{nil, "a", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := filterCompare(tt.value, tt.operand)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("filterCompare(%v, %q) = %d, %v, want %d, %v", tt.value, tt.operand, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseFilterTime(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "now", want: now},
+		{value: "now-7d", want: now.Add(-7 * 24 * time.Hour)},
+		{value: "now+2h", want: now.Add(2 * time.Hour)},
+		{value: "now-30m", want: now.Add(-30 * time.Minute)},
+		{value: "now-1w", want: now.Add(-7 * 24 * time.Hour)},
+		{value: " now-15s ", want: now.Add(-15 * time.Second)},
+		{value: "2026-01-02", want: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-01-02T03:04:05Z", want: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{value: "now-7y", wantErr: true},
+		{value: "now-d", wantErr: true},
+		{value: "now7d", wantErr: true},
+		{value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFilterTime(tt.value, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.value)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("%q: got %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplitConditions(t *testing.T) {
+	conditions := []models.FilterCondition{
+		{Property: "WorkspaceId", Value: "ws-1"},
+		{Property: "WorkspaceId", Operator: "!=", Value: "ws-2"},
+		{Property: "State", Value: "AVAILABLE"},
+		{Property: "DirectoryId", Operator: "=", Value: "d-1"},
+	}
+	awsConditions, clientConditions := splitConditions(conditions, "WorkspaceId", "DirectoryId")
+	if !reflect.DeepEqual(awsConditions, []models.FilterCondition{conditions[0], conditions[3]}) {
+		t.Errorf("unexpected AWS conditions %v", awsConditions)
+	}
+	if !reflect.DeepEqual(clientConditions, []models.FilterCondition{conditions[1], conditions[2]}) {
+		t.Errorf("unexpected client conditions %v", clientConditions)
+	}
+}
+
+func TestImageFilters(t *testing.T) {
+	conditions := []models.FilterCondition{
+		{Property: "Visibility", Value: "PRIVATE"},
+		{Property: "Platform", Value: "WINDOWS_SERVER_2022"},
+		{Property: "Platform", Operator: "!=", Value: "AMAZON_LINUX2"},
+	}
+	si := NewSammImage(nil, conditions, 0)
+	si.createFilter(nil)
+	if aws.StringValue(si.filter.Type) != "PRIVATE" {
+		t.Errorf("Visibility must be sent as the Type of DescribeImages, got %v", si.filter.Type)
+	}
+	if !reflect.DeepEqual(si.ClientFilterConditions(), conditions[1:]) {
+		t.Errorf("Platform must be filtered client side, got %v", si.ClientFilterConditions())
+	}
+
+	si = NewSammImage(nil, []models.FilterCondition{
+		{Property: "Platform", Value: "WINDOWS_SERVER_2022"},
+		{Property: "Visibility", Operator: "!=", Value: "PUBLIC"},
+	}, 0)
+	si.UpdateElements([]interface{}{
+		&appstream.Image{Name: aws.String("img-1"), Platform: aws.String("WINDOWS_SERVER_2022"), Visibility: aws.String("PRIVATE")},
+		&appstream.Image{Name: aws.String("img-2"), Platform: aws.String("WINDOWS_SERVER_2022"), Visibility: aws.String("PUBLIC")},
+		&appstream.Image{Name: aws.String("img-3"), Platform: aws.String("AMAZON_LINUX2"), Visibility: aws.String("PRIVATE")},
+	}, nil, true)
+	rows, err := FilterElements(si, si.ClientFilterConditions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, []int{0}) {
+		t.Fatalf("expected the private Windows image only, got rows %v", rows)
+	}
+
+	sb := NewSammImageBuilder(nil, []models.FilterCondition{{Property: "Platform", Value: "WINDOWS_SERVER_2022"}}, 0)
+	sb.createFilter(nil)
+	if len(sb.ClientFilterConditions()) != 1 {
+		t.Errorf("Platform must be filtered client side for the image builders, got %v", sb.ClientFilterConditions())
+	}
+}
+
+func TestWorkspaceDirectoryNameFilter(t *testing.T) {
+	sd := NewSammWorkspacesDirectory(nil, []models.FilterCondition{{Property: "DirectoryName", Value: "corp.example.com"}}, 0)
+	sd.createFilter(nil)
+	if len(sd.filter.DirectoryIds) != 0 {
+		t.Errorf("no DirectoryIds expected, got %v", sd.filter.DirectoryIds)
+	}
+	if len(sd.filter.WorkspaceDirectoryNames) != 1 || aws.StringValue(sd.filter.WorkspaceDirectoryNames[0]) != "corp.example.com" {
+		t.Errorf("a name only filter must set WorkspaceDirectoryNames, got %v", sd.filter.WorkspaceDirectoryNames)
+	}
+}
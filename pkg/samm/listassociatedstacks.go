@@ -12,6 +12,7 @@ import (
 
 type SammAssociatedStacks struct {
     attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
     defaultFieldList []string
     elements []interface{}
     filter appstream.ListAssociatedStacksInput
@@ -52,16 +53,20 @@ func (samm SammAssociatedStacks) AttributeType(attributeName string) (interface{
     return attr, ok
 }
 
+func (samm SammAssociatedStacks) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
 func (samm *SammAssociatedStacks) createFilter(NextToken *string) {
     if NextToken != nil {
         samm.filter.SetNextToken(*NextToken)
     }
-    for _, filterCondition := range samm.filterConditions {
-        switch property := filterCondition.Property; property {
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "FleetName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
         case "FleetName":
 			samm.filter.SetFleetName(filterCondition.Value)
-        default:
-            log.DefaultLogger.Warn("Invalid property in filter", "property", property)
         }
     }
     log.DefaultLogger.Debug("Input", "filter", samm.filter)
@@ -110,7 +115,6 @@ func (samm *SammAssociatedStacks) Query(elements []interface{}) ([]interface{},
             return elements, NextToken, nil
         }
     }
-    return elements, nil, err
 }
 
 func (samm *SammAssociatedStacks) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
@@ -121,6 +125,7 @@ func (samm *SammAssociatedStacks) UpdateElements(cachedElements interface{}, nex
 
     if cacheIsValid {
         samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
         return nil
     }
 
@@ -0,0 +1,151 @@
+package samm
+
+import (
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/service/appstream"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+
+)
+
+type SammEntitledApplication struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    filter *appstream.ListEntitledApplicationsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    svc *appstream.AppStream
+}
+
+func NewSammEntitledApplication(svc *appstream.AppStream, filterConditions []models.FilterCondition, Limit int) SammEntitledApplication {
+    return SammEntitledApplication{
+        attributes: map[string]interface{} {
+            "ApplicationIdentifier": []*string{},
+        },
+        defaultFieldList: []string {
+            "ApplicationIdentifier",
+        },
+        filterConditions: filterConditions,
+        limit: Limit,
+        svc: svc,
+    }
+}
+
+func (samm SammEntitledApplication) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*appstream.EntitledApplication)
+    switch name {
+    case "ApplicationIdentifier":
+        field.Append(object.ApplicationIdentifier)
+    }
+}
+
+func (samm SammEntitledApplication) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammEntitledApplication) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammEntitledApplication) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammEntitledApplication) createFilter(NextToken *string) {
+    samm.filter = &appstream.ListEntitledApplicationsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    /* StackName and EntitlementName are mandatory for ListEntitledApplications */
+    awsConditions, clientConditions := splitConditions(samm.filterConditions, "StackName", "EntitlementName")
+    samm.clientFilterConditions = clientConditions
+    for _, filterCondition := range awsConditions {
+        switch filterCondition.Property {
+        case "StackName":
+            samm.filter.SetStackName(filterCondition.Value)
+        case "EntitlementName":
+            samm.filter.SetEntitlementName(filterCondition.Value)
+        }
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammEntitledApplication) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammEntitledApplication) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammEntitledApplication) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammEntitledApplication) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammEntitledApplication) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *appstream.ListEntitledApplicationsOutput
+
+        awsoutput, err = samm.svc.ListEntitledApplications(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.EntitledApplications {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("appstream.ListEntitledApplications Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammEntitledApplication) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,324 @@
+package samm
+
+import (
+    "encoding/json"
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/cloudtrail"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+)
+
+/* LookupEvents accepts a single lookup attribute. The first equal condition
+ * on a lookup attribute, in the order of lookupAttributeKeys, is sent to
+ * AWS and the other conditions are applied on the collected events. */
+var lookupAttributeKeys = []string{
+    "EventId",
+    "EventName",
+    "Username",
+    "ResourceName",
+    "AccessKeyId",
+    "ResourceType",
+    "EventSource",
+    "ReadOnly",
+}
+
+var workspaceIdPattern = regexp.MustCompile(`ws-[0-9a-z]{9}`)
+
+/* CloudTrailEventDetail is the part of the CloudTrailEvent document used
+ * for the fields that LookupEvents does not return. */
+type CloudTrailEventDetail struct {
+    ErrorCode string `json:"errorCode"`
+    ErrorMessage string `json:"errorMessage"`
+    SourceIPAddress string `json:"sourceIPAddress"`
+    RequestParameters map[string]interface{} `json:"requestParameters"`
+}
+
+type SammCloudTrailEvent struct {
+    attributes map[string]interface{}
+    clientFilterConditions []models.FilterCondition
+    defaultFieldList []string
+    elements []interface{}
+    endTime time.Time
+    filter *cloudtrail.LookupEventsInput
+    filterConditions []models.FilterCondition
+    limit int
+    nextToken *string
+    startTime time.Time
+    svc *cloudtrail.CloudTrail
+}
+
+func NewSammCloudTrailEvent(svc *cloudtrail.CloudTrail, filterConditions []models.FilterCondition, Limit int, startTime time.Time, endTime time.Time) SammCloudTrailEvent {
+    return SammCloudTrailEvent{
+        attributes: map[string]interface{} {
+            "AccessKeyId": []*string{},
+            "CloudTrailEvent": []*string{},
+            "ErrorCode": []*string{},
+            "ErrorMessage": []*string{},
+            "EventId": []*string{},
+            "EventName": []*string{},
+            "EventSource": []*string{},
+            "EventTime": []*time.Time{},
+            "FleetName": []*string{},
+            "ReadOnly": []*string{},
+            "ResourceNames": []string{},
+            "ResourceTypes": []string{},
+            "SourceIPAddress": []*string{},
+            "StackName": []*string{},
+            "Username": []*string{},
+            "WorkspaceIds": []string{},
+        },
+        defaultFieldList: []string {
+            "EventTime",
+            "EventName",
+            "EventSource",
+            "Username",
+            "WorkspaceIds",
+            "FleetName",
+            "ErrorCode",
+        },
+        endTime: endTime,
+        filterConditions: filterConditions,
+        limit: Limit,
+        startTime: startTime,
+        svc: svc,
+    }
+}
+
+func (samm SammCloudTrailEvent) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.elements[elementIndex].(*cloudtrail.Event)
+    switch name {
+    case "AccessKeyId":
+        field.Append(object.AccessKeyId)
+    case "CloudTrailEvent":
+        field.Append(object.CloudTrailEvent)
+    case "ErrorCode":
+        field.Append(nonEmpty(EventDetail(object).ErrorCode))
+    case "ErrorMessage":
+        field.Append(nonEmpty(EventDetail(object).ErrorMessage))
+    case "EventId":
+        field.Append(object.EventId)
+    case "EventName":
+        field.Append(object.EventName)
+    case "EventSource":
+        field.Append(object.EventSource)
+    case "EventTime":
+        field.Append(object.EventTime)
+    case "FleetName":
+        field.Append(nonEmpty(EventResourceName(object, "Fleet")))
+    case "ReadOnly":
+        field.Append(object.ReadOnly)
+    case "ResourceNames":
+        temp := make([]string, len(object.Resources))
+        for i, resource := range object.Resources {
+            temp[i] = aws.StringValue(resource.ResourceName)
+        }
+        field.Append(strings.Join(temp, ","))
+    case "ResourceTypes":
+        temp := make([]string, len(object.Resources))
+        for i, resource := range object.Resources {
+            temp[i] = aws.StringValue(resource.ResourceType)
+        }
+        field.Append(strings.Join(temp, ","))
+    case "SourceIPAddress":
+        field.Append(nonEmpty(EventDetail(object).SourceIPAddress))
+    case "StackName":
+        field.Append(nonEmpty(EventResourceName(object, "Stack")))
+    case "Username":
+        field.Append(object.Username)
+    case "WorkspaceIds":
+        field.Append(strings.Join(EventWorkspaceIds(object), ","))
+    }
+}
+
+func nonEmpty(value string) *string {
+    if value == "" {
+        return nil
+    }
+    return &value
+}
+
+/* EventDetail decodes the CloudTrailEvent document of an event. */
+func EventDetail(event *cloudtrail.Event) CloudTrailEventDetail {
+    var detail CloudTrailEventDetail
+    if event.CloudTrailEvent != nil {
+        if err := json.Unmarshal([]byte(*event.CloudTrailEvent), &detail); err != nil {
+            log.DefaultLogger.Debug("Invalid CloudTrailEvent", "eventId", aws.StringValue(event.EventId), "error", err.Error())
+        }
+    }
+    return detail
+}
+
+/* EventWorkspaceIds returns the workspace ids found in the resources and
+ * the request parameters of an event. */
+func EventWorkspaceIds(event *cloudtrail.Event) []string {
+    ids := []string{}
+    seen := map[string]bool{}
+    add := func(id string) {
+        if !seen[id] {
+            seen[id] = true
+            ids = append(ids, id)
+        }
+    }
+    for _, resource := range event.Resources {
+        if workspaceIdPattern.MatchString(aws.StringValue(resource.ResourceName)) {
+            add(aws.StringValue(resource.ResourceName))
+        }
+    }
+    parameters, _ := json.Marshal(EventDetail(event).RequestParameters)
+    for _, id := range workspaceIdPattern.FindAllString(string(parameters), -1) {
+        add(id)
+    }
+    return ids
+}
+
+/* EventResourceName returns the name of the AppStream fleet or stack of an
+ * event: "name" for the operations on the resource itself (StopFleet),
+ * else "fleetName" or "stackName". */
+func EventResourceName(event *cloudtrail.Event, resource string) string {
+    parameters := EventDetail(event).RequestParameters
+    key := strings.ToLower(resource[:1]) + resource[1:] + "Name"
+    if strings.HasSuffix(aws.StringValue(event.EventName), resource) {
+        key = "name"
+    }
+    if value, ok := parameters[key].(string); ok {
+        return value
+    }
+    return ""
+}
+
+/* SortEvents orders events from the oldest to the most recent. */
+func SortEvents(events []*cloudtrail.Event) {
+    sort.SliceStable(events, func(a, b int) bool {
+        return aws.TimeValue(events[a].EventTime).Before(aws.TimeValue(events[b].EventTime))
+    })
+}
+
+func (samm SammCloudTrailEvent) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammCloudTrailEvent) AttributeType(attributeName string) (interface{}, bool) {
+    attr, ok := samm.attributes[attributeName]
+    return attr, ok
+}
+
+func (samm SammCloudTrailEvent) ClientFilterConditions() []models.FilterCondition {
+    return samm.clientFilterConditions
+}
+
+func (samm *SammCloudTrailEvent) createFilter(NextToken *string) {
+    samm.clientFilterConditions = []models.FilterCondition{}
+    samm.filter = &cloudtrail.LookupEventsInput{}
+
+    if NextToken != nil {
+        samm.filter.SetNextToken(*NextToken)
+    }
+    if !samm.startTime.IsZero() {
+        samm.filter.SetStartTime(samm.startTime)
+    }
+    if !samm.endTime.IsZero() {
+        samm.filter.SetEndTime(samm.endTime)
+    }
+    lookup := -1
+    for _, key := range lookupAttributeKeys {
+        for i, filterCondition := range samm.filterConditions {
+            if lookup < 0 && filterCondition.IsEqual() && filterCondition.Property == key {
+                lookup = i
+            }
+        }
+    }
+    for i, filterCondition := range samm.filterConditions {
+        if i == lookup {
+            samm.filter.SetLookupAttributes([]*cloudtrail.LookupAttribute{
+                {
+                    AttributeKey: aws.String(filterCondition.Property),
+                    AttributeValue: aws.String(filterCondition.Value),
+                },
+            })
+            continue
+        }
+        samm.clientFilterConditions = append(samm.clientFilterConditions, filterCondition)
+    }
+    log.DefaultLogger.Debug("Input", "filter", samm.filter)
+}
+
+func (samm SammCloudTrailEvent) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammCloudTrailEvent) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammCloudTrailEvent) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammCloudTrailEvent) NextToken() *string {
+    return samm.nextToken
+}
+
+func (samm *SammCloudTrailEvent) Query(elements []interface{}) ([]interface{}, *string, error) {
+    var err error
+    NextToken := samm.filter.NextToken
+    for {
+        var awsoutput *cloudtrail.LookupEventsOutput
+
+        awsoutput, err = samm.svc.LookupEvents(samm.filter)
+        if (err != nil) {
+            log.DefaultLogger.Error("Unable to collect objects.", "error", err.Error())
+            return elements, NextToken, err
+        }
+        for _, e := range awsoutput.Events {
+            elements = append(elements, e)
+        }
+        log.DefaultLogger.Debug("cloudtrail.LookupEvents Elements.", "cache_length", len(elements))
+
+        NextToken = awsoutput.NextToken
+        if NextToken == nil {
+            return elements, nil, nil
+        } else {
+            samm.filter.SetNextToken(*NextToken)
+        }
+        if samm.limit > 0 && len(elements) >= samm.limit {
+            log.DefaultLogger.Info("Limit Reached")
+            return elements, NextToken, nil
+        }
+    }
+}
+
+func (samm *SammCloudTrailEvent) UpdateElements(cachedElements interface{}, nextToken *string, cacheIsValid bool) (error) {
+    var err error
+
+    elements := cachedElements.([]interface{})
+    NextToken := nextToken
+
+    if cacheIsValid {
+        samm.elements = elements
+        samm.clientFilterConditions = samm.filterConditions
+        return nil
+    }
+
+    /* Process Filters */
+    samm.createFilter(NextToken)
+    /* End Process Filters */
+
+    /* Collect Data */
+    elements, samm.nextToken, err = samm.Query(elements)
+    /* End Collect Data */
+
+    samm.elements = elements
+    log.DefaultLogger.Debug("UpdateElements", "len(elements)", len(elements), "NextToken", NextToken)
+    return err
+}
@@ -0,0 +1,197 @@
+package samm
+
+import (
+    "fmt"
+    "reflect"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+/* Nested attributes are exposed as dotted fields, for example
+ * "WorkspaceProperties.RunningMode" or "ComputeCapacityStatus.Available".
+ * Scalar attributes keep their type. Attributes found inside lists are
+ * returned as a comma separated string of all the values. */
+const nestedMaxDepth = 3
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func IsNestedField(name string) bool {
+    return strings.Contains(name, ".") && !IsTagField(name)
+}
+
+/* AttributeNames returns the root attributes of the element, in the order
+ * of the fields of the prototype, without calling AWS: nested and tag
+ * fields are not included. */
+func AttributeNames(se SammElement, prototype interface{}) []string {
+    names := []string{}
+    prototypeType := reflect.TypeOf(prototype)
+    for prototypeType.Kind() == reflect.Ptr {
+        prototypeType = prototypeType.Elem()
+    }
+    for i := 0; i < prototypeType.NumField(); i++ {
+        structField := prototypeType.Field(i)
+        if !structField.IsExported() {
+            continue
+        }
+        if _, ok := se.AttributeType(structField.Name); ok {
+            names = append(names, structField.Name)
+        }
+    }
+    return names
+}
+
+/* addNestedAttributes registers the dotted attributes found under each of
+ * the root attributes of the prototype and returns their sorted names. */
+func addNestedAttributes(attributes map[string]interface{}, prototype interface{}, roots ...string) []string {
+    names := []string{}
+    prototypeType := reflect.TypeOf(prototype)
+    for prototypeType.Kind() == reflect.Ptr {
+        prototypeType = prototypeType.Elem()
+    }
+    for _, root := range roots {
+        structField, ok := prototypeType.FieldByName(root)
+        if !ok {
+            continue
+        }
+        walkNestedType(structField.Type, root, false, 0, attributes, &names)
+    }
+    sort.Strings(names)
+    return names
+}
+
+func walkNestedType(fieldType reflect.Type, path string, inList bool, depth int, attributes map[string]interface{}, names *[]string) {
+    baseType := fieldType
+    for baseType.Kind() == reflect.Ptr {
+        baseType = baseType.Elem()
+    }
+    switch {
+    case baseType.Kind() == reflect.Struct && baseType != timeType:
+        if depth >= nestedMaxDepth {
+            return
+        }
+        for i := 0; i < baseType.NumField(); i++ {
+            structField := baseType.Field(i)
+            if !structField.IsExported() {
+                continue
+            }
+            walkNestedType(structField.Type, path + "." + structField.Name, inList, depth + 1, attributes, names)
+        }
+    case baseType.Kind() == reflect.Slice:
+        elemType := baseType.Elem()
+        for elemType.Kind() == reflect.Ptr {
+            elemType = elemType.Elem()
+        }
+        if elemType.Kind() == reflect.Struct && elemType != timeType {
+            walkNestedType(elemType, path, true, depth, attributes, names)
+        } else if depth > 0 {
+            addNestedAttribute(path, []string{}, attributes, names)
+        }
+    case baseType.Kind() == reflect.Map:
+        return
+    case depth == 0:
+        return
+    case inList:
+        addNestedAttribute(path, []string{}, attributes, names)
+    default:
+        addNestedAttribute(path, nestedFieldType(fieldType), attributes, names)
+    }
+}
+
+func addNestedAttribute(path string, fieldType interface{}, attributes map[string]interface{}, names *[]string) {
+    if fieldType == nil {
+        return
+    }
+    if _, ok := attributes[path]; !ok {
+        attributes[path] = fieldType
+    }
+    *names = append(*names, path)
+}
+
+func nestedFieldType(fieldType reflect.Type) interface{} {
+    switch fieldType {
+    case reflect.TypeOf((*string)(nil)):
+        return []*string{}
+    case reflect.TypeOf((*int64)(nil)):
+        return []*int64{}
+    case reflect.TypeOf((*float64)(nil)):
+        return []*float64{}
+    case reflect.TypeOf((*bool)(nil)):
+        return []*bool{}
+    case reflect.TypeOf((*time.Time)(nil)):
+        return []*time.Time{}
+    }
+    return nil
+}
+
+/* appendNested appends the value found at a dotted path of the object
+ * (for example "CapacityStatus.ActiveUserSessions"). When a structure in
+ * the path is nil, a nil value is appended instead. String fields get the
+ * values found inside lists as a comma separated string. */
+func appendNested(field *data.Field, object interface{}, path string) {
+    leaves := collectNested(reflect.ValueOf(object), strings.Split(path, "."))
+    if field.Type() == data.FieldTypeString {
+        values := []string{}
+        for _, leaf := range leaves {
+            values = append(values, nestedStrings(leaf)...)
+        }
+        field.Append(strings.Join(values, ","))
+        return
+    }
+    if len(leaves) != 1 {
+        field.Extend(1)
+        return
+    }
+    field.Append(leaves[0].Interface())
+}
+
+func collectNested(value reflect.Value, names []string) []reflect.Value {
+    if len(names) == 0 {
+        return []reflect.Value{value}
+    }
+    for value.IsValid() && value.Kind() == reflect.Ptr {
+        if value.IsNil() {
+            return nil
+        }
+        value = value.Elem()
+    }
+    if !value.IsValid() {
+        return nil
+    }
+    switch value.Kind() {
+    case reflect.Slice:
+        leaves := []reflect.Value{}
+        for i := 0; i < value.Len(); i++ {
+            leaves = append(leaves, collectNested(value.Index(i), names)...)
+        }
+        return leaves
+    case reflect.Struct:
+        return collectNested(value.FieldByName(names[0]), names[1:])
+    }
+    return nil
+}
+
+func nestedStrings(value reflect.Value) []string {
+    for value.IsValid() && value.Kind() == reflect.Ptr {
+        if value.IsNil() {
+            return nil
+        }
+        value = value.Elem()
+    }
+    if !value.IsValid() {
+        return nil
+    }
+    if value.Kind() == reflect.Slice {
+        values := []string{}
+        for i := 0; i < value.Len(); i++ {
+            values = append(values, nestedStrings(value.Index(i))...)
+        }
+        return values
+    }
+    if t, ok := value.Interface().(time.Time); ok {
+        return []string{t.Format(time.RFC3339)}
+    }
+    return []string{fmt.Sprint(value.Interface())}
+}
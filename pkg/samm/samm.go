@@ -1,6 +1,8 @@
 package samm
 
 import (
+    "github.com/samana-group/sammaws/pkg/models"
+
     "github.com/grafana/grafana-plugin-sdk-go/data"
 )
 
@@ -12,4 +14,6 @@ type SammElement interface {
     Elements() []interface{}
     At(int) interface{}
     NextToken() *string
+    /* Filter conditions AWS did not apply, evaluated by FilterElements */
+    ClientFilterConditions() []models.FilterCondition
 }
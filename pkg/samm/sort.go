@@ -0,0 +1,105 @@
+package samm
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+/* SortElements orders the rows on the sort keys, the first key being the
+ * most significant. Rows without a value are always placed last. */
+func SortElements(elements SammElement, rows []int, sortKeys []models.SortKey) error {
+    if len(sortKeys) == 0 {
+        return nil
+    }
+    fields := make([]*data.Field, len(sortKeys))
+    for k, sortKey := range sortKeys {
+        fieldType, ok := elements.AttributeType(sortKey.Property)
+        if !ok {
+            return fmt.Errorf("Invalid property in sort %s.", sortKey.Property)
+        }
+        fields[k] = data.NewField(sortKey.Property, nil, fieldType)
+        for i := 0; i < elements.Len(); i++ {
+            elements.AppendData(i, fields[k], sortKey.Property)
+        }
+    }
+    SortRows(rows, fields, sortKeys)
+    return nil
+}
+
+/* SortRows orders the rows on the values of the fields, fields[k] holding
+ * the values of sortKeys[k]. Rows without a value are always placed last. */
+func SortRows(rows []int, fields []*data.Field, sortKeys []models.SortKey) {
+    sort.SliceStable(rows, func(a, b int) bool {
+        for k, sortKey := range sortKeys {
+            valueA := FieldValue(fields[k], rows[a])
+            valueB := FieldValue(fields[k], rows[b])
+            if valueA == nil || valueB == nil {
+                if valueA == nil && valueB == nil {
+                    continue
+                }
+                return valueB == nil
+            }
+            c := CompareValues(valueA, valueB)
+            if c == 0 {
+                continue
+            }
+            if sortKey.Desc {
+                return c > 0
+            }
+            return c < 0
+        }
+        return false
+    })
+}
+
+/* CompareValues compares two values returned by FieldValue. */
+func CompareValues(a interface{}, b interface{}) int {
+    switch va := a.(type) {
+    case float64:
+        if vb, ok := b.(float64); ok {
+            switch {
+            case va < vb:
+                return -1
+            case va > vb:
+                return 1
+            }
+            return 0
+        }
+    case bool:
+        if vb, ok := b.(bool); ok {
+            switch {
+            case va == vb:
+                return 0
+            case !va:
+                return -1
+            }
+            return 1
+        }
+    case time.Time:
+        if vb, ok := b.(time.Time); ok {
+            return va.Compare(vb)
+        }
+    }
+    return strings.Compare(ValueString(a), ValueString(b))
+}
+
+/* PageRows applies the offset and the row limit to the rows. A row limit
+ * of zero or less keeps all the rows. */
+func PageRows(rows []int, offset int, rowLimit int) []int {
+    if offset > 0 {
+        if offset >= len(rows) {
+            return []int{}
+        }
+        rows = rows[offset:]
+    }
+    if rowLimit > 0 && rowLimit < len(rows) {
+        rows = rows[:rowLimit]
+    }
+    return rows
+}
@@ -0,0 +1,144 @@
+package samm
+
+import (
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+/* SammWorkspace360 joins each workspace with its connection status, its
+ * bundle and its directory. The workspace fields keep their names, the
+ * joined fields are listed in workspace360Fields. */
+type SammWorkspace360Item struct {
+    Workspace *workspaces.Workspace
+    ConnectionStatus *workspaces.WorkspaceConnectionStatus
+    Bundle *workspaces.WorkspaceBundle
+    Directory *workspaces.WorkspaceDirectory
+}
+
+type SammWorkspace360 struct {
+    attributes map[string]interface{}
+    defaultFieldList []string
+    elements []interface{}
+    workspaces SammWorkspace
+}
+
+/* Joined field name and its path in SammWorkspace360Item */
+var workspace360Fields = map[string]string{
+    "BundleName": "Bundle.Name",
+    "ComputeType": "Bundle.ComputeType.Name",
+    "ConnectionState": "ConnectionStatus.ConnectionState",
+    "ConnectionStateCheckTimestamp": "ConnectionStatus.ConnectionStateCheckTimestamp",
+    "DirectoryAlias": "Directory.Alias",
+    "DirectoryName": "Directory.DirectoryName",
+    "DirectoryState": "Directory.State",
+    "DirectoryType": "Directory.DirectoryType",
+    "LastKnownUserConnectionTimestamp": "ConnectionStatus.LastKnownUserConnectionTimestamp",
+    "RootStorageCapacity": "Bundle.RootStorage.Capacity",
+    "UserStorageCapacity": "Bundle.UserStorage.Capacity",
+}
+
+func NewSammWorkspace360(sw SammWorkspace, connectionStatus []interface{}, bundles []interface{}, directories []interface{}) SammWorkspace360 {
+    statusById := map[string]*workspaces.WorkspaceConnectionStatus{}
+    for _, e := range connectionStatus {
+        status := e.(*workspaces.WorkspaceConnectionStatus)
+        statusById[aws.StringValue(status.WorkspaceId)] = status
+    }
+    bundleById := map[string]*workspaces.WorkspaceBundle{}
+    for _, e := range bundles {
+        bundle := e.(*workspaces.WorkspaceBundle)
+        bundleById[aws.StringValue(bundle.BundleId)] = bundle
+    }
+    directoryById := map[string]*workspaces.WorkspaceDirectory{}
+    for _, e := range directories {
+        directory := e.(*workspaces.WorkspaceDirectory)
+        directoryById[aws.StringValue(directory.DirectoryId)] = directory
+    }
+
+    elements := make([]interface{}, len(sw.elements))
+    for i, e := range sw.elements {
+        workspace := e.(*workspaces.Workspace)
+        elements[i] = &SammWorkspace360Item{
+            Workspace: workspace,
+            ConnectionStatus: statusById[aws.StringValue(workspace.WorkspaceId)],
+            Bundle: bundleById[aws.StringValue(workspace.BundleId)],
+            Directory: directoryById[aws.StringValue(workspace.DirectoryId)],
+        }
+    }
+
+    return SammWorkspace360{
+        attributes: map[string]interface{} {
+            "BundleName": []*string{},
+            "ComputeType": []*string{},
+            "ConnectionState": []*string{},
+            "ConnectionStateCheckTimestamp": []*time.Time{},
+            "DirectoryAlias": []*string{},
+            "DirectoryName": []*string{},
+            "DirectoryState": []*string{},
+            "DirectoryType": []*string{},
+            "LastKnownUserConnectionTimestamp": []*time.Time{},
+            "RootStorageCapacity": []*string{},
+            "UserStorageCapacity": []*string{},
+        },
+        defaultFieldList: []string {
+            "WorkspaceId",
+            "UserName",
+            "ComputerName",
+            "State",
+            "ConnectionState",
+            "LastKnownUserConnectionTimestamp",
+            "BundleName",
+            "ComputeType",
+            "DirectoryAlias",
+        },
+        elements: elements,
+        workspaces: sw,
+    }
+}
+
+func (samm SammWorkspace360) AppendData(elementIndex int, field *data.Field, name string) {
+    if path, ok := workspace360Fields[name]; ok {
+        appendNested(field, samm.elements[elementIndex], path)
+        return
+    }
+    samm.workspaces.AppendData(elementIndex, field, name)
+}
+
+func (samm SammWorkspace360) At(index int) interface{} {
+    if index >= 0 && index < len(samm.elements) {
+        return samm.elements[index]
+    }
+    return nil
+}
+
+func (samm SammWorkspace360) AttributeType(attributeName string) (interface{}, bool) {
+    if attr, ok := samm.attributes[attributeName]; ok {
+        return attr, ok
+    }
+    return samm.workspaces.AttributeType(attributeName)
+}
+
+func (samm SammWorkspace360) ClientFilterConditions() []models.FilterCondition {
+    return samm.workspaces.ClientFilterConditions()
+}
+
+func (samm SammWorkspace360) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammWorkspace360) Elements() []interface{} {
+    return samm.elements
+}
+
+func (samm SammWorkspace360) Len() int {
+    return len(samm.elements)
+}
+
+func (samm SammWorkspace360) NextToken() *string {
+    return nil
+}
@@ -0,0 +1,140 @@
+package samm
+
+import (
+    "time"
+
+    "github.com/samana-group/sammaws/pkg/models"
+
+    "github.com/aws/aws-sdk-go/aws"
+    "github.com/aws/aws-sdk-go/service/workspaces"
+
+    "github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+/* SammWorkspaceUsage adds to the joined workspaces the days since the last
+ * user connection and a usage status:
+ *   never-used            no known user connection
+ *   idle                  no connection for more than idleDays
+ *   alwayson-rarely-used  AlwaysOn without connection for more than rarelyUsedDays
+ *   active                the others
+ *   unknown               no connection status */
+const (
+    DefaultIdleDays = 30
+    DefaultRarelyUsedDays = 7
+)
+
+type SammWorkspaceUsage struct {
+    attributes map[string]interface{}
+    defaultFieldList []string
+    idleDays int
+    now time.Time
+    rarelyUsedDays int
+    workspaces SammWorkspace360
+}
+
+func NewSammWorkspaceUsage(s360 SammWorkspace360, idleDays int, rarelyUsedDays int, now time.Time) SammWorkspaceUsage {
+    if idleDays <= 0 {
+        idleDays = DefaultIdleDays
+    }
+    if rarelyUsedDays <= 0 {
+        rarelyUsedDays = DefaultRarelyUsedDays
+    }
+    return SammWorkspaceUsage{
+        attributes: map[string]interface{} {
+            "DaysSinceLastConnection": []*int64{},
+            "RunningMode": []*string{},
+            "UsageStatus": []*string{},
+        },
+        defaultFieldList: []string {
+            "WorkspaceId",
+            "UserName",
+            "ComputerName",
+            "State",
+            "RunningMode",
+            "ComputeType",
+            "LastKnownUserConnectionTimestamp",
+            "DaysSinceLastConnection",
+            "UsageStatus",
+        },
+        idleDays: idleDays,
+        now: now,
+        rarelyUsedDays: rarelyUsedDays,
+        workspaces: s360,
+    }
+}
+
+func (samm SammWorkspaceUsage) AppendData(elementIndex int, field *data.Field, name string) {
+    object := samm.workspaces.elements[elementIndex].(*SammWorkspace360Item)
+    switch name {
+    case "DaysSinceLastConnection":
+        field.Append(samm.daysSinceLastConnection(object))
+    case "RunningMode":
+        field.Append(runningMode(object))
+    case "UsageStatus":
+        field.Append(aws.String(samm.usageStatus(object)))
+    default:
+        samm.workspaces.AppendData(elementIndex, field, name)
+    }
+}
+
+func runningMode(object *SammWorkspace360Item) *string {
+    if object.Workspace.WorkspaceProperties == nil {
+        return nil
+    }
+    return object.Workspace.WorkspaceProperties.RunningMode
+}
+
+func (samm SammWorkspaceUsage) daysSinceLastConnection(object *SammWorkspace360Item) *int64 {
+    if object.ConnectionStatus == nil || object.ConnectionStatus.LastKnownUserConnectionTimestamp == nil {
+        return nil
+    }
+    days := int64(samm.now.Sub(*object.ConnectionStatus.LastKnownUserConnectionTimestamp) / (24 * time.Hour))
+    return &days
+}
+
+func (samm SammWorkspaceUsage) usageStatus(object *SammWorkspace360Item) string {
+    if object.ConnectionStatus == nil {
+        return "unknown"
+    }
+    days := samm.daysSinceLastConnection(object)
+    switch {
+    case days == nil:
+        return "never-used"
+    case *days > int64(samm.idleDays):
+        return "idle"
+    case *days > int64(samm.rarelyUsedDays) && aws.StringValue(runningMode(object)) == workspaces.RunningModeAlwaysOn:
+        return "alwayson-rarely-used"
+    }
+    return "active"
+}
+
+func (samm SammWorkspaceUsage) At(index int) interface{} {
+    return samm.workspaces.At(index)
+}
+
+func (samm SammWorkspaceUsage) AttributeType(attributeName string) (interface{}, bool) {
+    if attr, ok := samm.attributes[attributeName]; ok {
+        return attr, ok
+    }
+    return samm.workspaces.AttributeType(attributeName)
+}
+
+func (samm SammWorkspaceUsage) ClientFilterConditions() []models.FilterCondition {
+    return samm.workspaces.ClientFilterConditions()
+}
+
+func (samm SammWorkspaceUsage) DefaultFieldList() []string {
+    return samm.defaultFieldList
+}
+
+func (samm SammWorkspaceUsage) Elements() []interface{} {
+    return samm.workspaces.Elements()
+}
+
+func (samm SammWorkspaceUsage) Len() int {
+    return samm.workspaces.Len()
+}
+
+func (samm SammWorkspaceUsage) NextToken() *string {
+    return nil
+}
@@ -0,0 +1,77 @@
+package samm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/workspaces"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+/* workspaceUsage returns the usage status and the days since the last
+ * connection of a single workspace. */
+func workspaceUsage(runningMode string, status *workspaces.WorkspaceConnectionStatus, idleDays int, rarelyUsedDays int, now time.Time) (string, *int64) {
+	sw := NewSammWorkspace(nil, nil, 0)
+	sw.UpdateElements([]interface{}{&workspaces.Workspace{
+		WorkspaceId:         aws.String("ws-1"),
+		WorkspaceProperties: &workspaces.WorkspaceProperties{RunningMode: aws.String(runningMode)},
+	}}, nil, true)
+	statuses := []interface{}{}
+	if status != nil {
+		status.WorkspaceId = aws.String("ws-1")
+		statuses = append(statuses, status)
+	}
+	usage := NewSammWorkspaceUsage(NewSammWorkspace360(sw, statuses, nil, nil), idleDays, rarelyUsedDays, now)
+	statusField := data.NewField("UsageStatus", nil, []*string{})
+	usage.AppendData(0, statusField, "UsageStatus")
+	daysField := data.NewField("DaysSinceLastConnection", nil, []*int64{})
+	usage.AppendData(0, daysField, "DaysSinceLastConnection")
+	return aws.StringValue(statusField.At(0).(*string)), daysField.At(0).(*int64)
+}
+
+func TestWorkspaceUsageStatus(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	connected := func(days int) *workspaces.WorkspaceConnectionStatus {
+		return &workspaces.WorkspaceConnectionStatus{
+			LastKnownUserConnectionTimestamp: aws.Time(now.Add(-time.Duration(days)*24*time.Hour - time.Hour)),
+		}
+	}
+	alwaysOn := workspaces.RunningModeAlwaysOn
+	autoStop := workspaces.RunningModeAutoStop
+	tests := []struct {
+		name           string
+		runningMode    string
+		status         *workspaces.WorkspaceConnectionStatus
+		idleDays       int
+		rarelyUsedDays int
+		want           string
+		wantDays       int64
+	}{
+		{name: "no connection status", runningMode: alwaysOn, want: "unknown", wantDays: -1},
+		{name: "no connection", runningMode: autoStop, status: &workspaces.WorkspaceConnectionStatus{}, want: "never-used", wantDays: -1},
+		{name: "idle after the default 30 days", runningMode: autoStop, status: connected(31), want: "idle", wantDays: 31},
+		{name: "idle AlwaysOn", runningMode: alwaysOn, status: connected(40), want: "idle", wantDays: 40},
+		{name: "not idle at 30 days", runningMode: autoStop, status: connected(30), want: "active", wantDays: 30},
+		{name: "AlwaysOn at 30 days", runningMode: alwaysOn, status: connected(30), want: "alwayson-rarely-used", wantDays: 30},
+		{name: "AlwaysOn after the default 7 days", runningMode: alwaysOn, status: connected(8), want: "alwayson-rarely-used", wantDays: 8},
+		{name: "AlwaysOn at 7 days", runningMode: alwaysOn, status: connected(7), want: "active", wantDays: 7},
+		{name: "AutoStop after 7 days", runningMode: autoStop, status: connected(8), want: "active", wantDays: 8},
+		{name: "connected today", runningMode: alwaysOn, status: connected(0), want: "active", wantDays: 0},
+		{name: "custom idle days", runningMode: autoStop, status: connected(11), idleDays: 10, want: "idle", wantDays: 11},
+		{name: "custom rarely used days", runningMode: alwaysOn, status: connected(3), idleDays: 10, rarelyUsedDays: 2, want: "alwayson-rarely-used", wantDays: 3},
+		{name: "negative days use the defaults", runningMode: alwaysOn, status: connected(8), idleDays: -1, rarelyUsedDays: -1, want: "alwayson-rarely-used", wantDays: 8},
+	}
+	for _, tt := range tests {
+		status, days := workspaceUsage(tt.runningMode, tt.status, tt.idleDays, tt.rarelyUsedDays, now)
+		if status != tt.want {
+			t.Errorf("%s: status %s, want %s", tt.name, status, tt.want)
+		}
+		switch {
+		case tt.wantDays < 0 && days != nil:
+			t.Errorf("%s: days %d, want none", tt.name, *days)
+		case tt.wantDays >= 0 && (days == nil || *days != tt.wantDays):
+			t.Errorf("%s: days %v, want %d", tt.name, days, tt.wantDays)
+		}
+	}
+}
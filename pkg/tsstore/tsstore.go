@@ -0,0 +1,173 @@
+package tsstore
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/grafana/grafana-plugin-sdk-go/backend/log"
+)
+
+/* The store keeps the samples of each sampler in a file of JSON lines named
+ * after the sampler, under the data directory of the datasource. Samples
+ * older than the retention are removed when the store is pruned. */
+type Sample struct {
+    Time   time.Time         `json:"t"`
+    Name   string            `json:"n"`
+    Labels map[string]string `json:"l,omitempty"`
+    Value  *float64          `json:"v"`
+}
+
+type Store struct {
+    mu        sync.Mutex
+    dir       string
+    retention time.Duration
+}
+
+/* The unsafe characters of the sampler names are escaped as %XX, "%" too,
+ * so that two samplers never share a file, e.g. "a b" and "a_b". */
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+func escapeName(sampler string) string {
+    return unsafeChars.ReplaceAllStringFunc(sampler, func(chars string) string {
+        escaped := ""
+        for i := 0; i < len(chars); i++ {
+            escaped += fmt.Sprintf("%%%02X", chars[i])
+        }
+        return escaped
+    })
+}
+
+func NewStore(dir string, retention time.Duration) (*Store, error) {
+    err := os.MkdirAll(dir, 0o750)
+    if err != nil {
+        return nil, err
+    }
+    return &Store{
+        dir: dir,
+        retention: retention,
+    }, nil
+}
+
+func (s *Store) path(sampler string) string {
+    return filepath.Join(s.dir, escapeName(sampler) + ".jsonl")
+}
+
+func (s *Store) Append(sampler string, samples []Sample) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    f, err := os.OpenFile(s.path(sampler), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    encoder := json.NewEncoder(f)
+    for _, sample := range samples {
+        err = encoder.Encode(sample)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+/* Range returns the samples of the sampler between from and to, in time order. */
+func (s *Store) Range(sampler string, from time.Time, to time.Time) ([]Sample, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    samples, err := s.read(sampler)
+    if err != nil {
+        return nil, err
+    }
+    out := []Sample{}
+    for _, sample := range samples {
+        if !sample.Time.Before(from) && !sample.Time.After(to) {
+            out = append(out, sample)
+        }
+    }
+    sort.SliceStable(out, func(a, b int) bool { return out[a].Time.Before(out[b].Time) })
+    return out, nil
+}
+
+func (s *Store) read(sampler string) ([]Sample, error) {
+    f, err := os.Open(s.path(sampler))
+    if os.IsNotExist(err) {
+        return []Sample{}, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    samples := []Sample{}
+    scanner := bufio.NewScanner(f)
+    scanner.Buffer(make([]byte, 64 * 1024), 1024 * 1024)
+    for scanner.Scan() {
+        var sample Sample
+        if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+            log.DefaultLogger.Warn("Invalid sample", "sampler", sampler, "error", err.Error())
+            continue
+        }
+        samples = append(samples, sample)
+    }
+    return samples, scanner.Err()
+}
+
+/* Prune rewrites the file of the sampler without the expired samples. */
+func (s *Store) Prune(sampler string, now time.Time) error {
+    if s.retention <= 0 {
+        return nil
+    }
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    samples, err := s.read(sampler)
+    if err != nil {
+        return err
+    }
+    limit := now.Add(-s.retention)
+    kept := []Sample{}
+    for _, sample := range samples {
+        if !sample.Time.Before(limit) {
+            kept = append(kept, sample)
+        }
+    }
+    if len(kept) == len(samples) {
+        return nil
+    }
+    tmp := s.path(sampler) + ".tmp"
+    f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+    if err != nil {
+        return err
+    }
+    encoder := json.NewEncoder(f)
+    for _, sample := range kept {
+        if err = encoder.Encode(sample); err != nil {
+            f.Close()
+            return err
+        }
+    }
+    if err = f.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp, s.path(sampler))
+}
+
+/* SeriesKey identifies a series by its name and labels. */
+func SeriesKey(sample Sample) string {
+    keys := make([]string, 0, len(sample.Labels))
+    for key := range sample.Labels {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    parts := []string{sample.Name}
+    for _, key := range keys {
+        parts = append(parts, key + "=" + sample.Labels[key])
+    }
+    return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,105 @@
+package tsstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func value(v float64) *float64 {
+	return &v
+}
+
+func TestStoreRange(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	err = s.Append("running", []Sample{
+		{Time: start.Add(2 * time.Minute), Name: "count", Value: value(3)},
+		{Time: start, Name: "count", Value: value(1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Append("running", []Sample{{Time: start.Add(time.Minute), Name: "count", Value: value(2)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := s.Range("running", start, start.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || *samples[0].Value != 1 || *samples[1].Value != 2 {
+		t.Fatalf("expected the first two samples in time order, got %+v", samples)
+	}
+
+	samples, err = s.Range("unknown", start, start.Add(time.Hour))
+	if err != nil || len(samples) != 0 {
+		t.Fatalf("a sampler without file must have no sample, got %+v, %v", samples, err)
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	err = s.Append("running", []Sample{
+		{Time: now.Add(-48 * time.Hour), Name: "count", Value: value(1)},
+		{Time: now.Add(-time.Hour), Name: "count", Value: value(2)},
+		{Time: now, Name: "count"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Prune("running", now); err != nil {
+		t.Fatal(err)
+	}
+	samples, err := s.Range("running", now.Add(-72*time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 || *samples[0].Value != 2 || samples[1].Value != nil {
+		t.Fatalf("expected the samples of the last day, got %+v", samples)
+	}
+	if _, err = os.Stat(s.path("running") + ".tmp"); !os.IsNotExist(err) {
+		t.Fatal("the temporary file must be renamed")
+	}
+
+	if err = s.Prune("unknown", now); err != nil {
+		t.Fatalf("pruning a sampler without file must succeed, got %v", err)
+	}
+}
+
+func TestStorePath(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewStore(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.path("../running idle"); got != filepath.Join(dir, "..%2Frunning%20idle.jsonl") {
+		t.Fatalf("the sampler name must not leave the directory, got %s", got)
+	}
+	for _, names := range [][2]string{{"a b", "a_b"}, {"a b", "a%20b"}, {"é", "%C3%A9"}} {
+		if s.path(names[0]) == s.path(names[1]) {
+			t.Errorf("the samplers %q and %q must not share a file", names[0], names[1])
+		}
+	}
+}
+
+func TestSeriesKey(t *testing.T) {
+	a := SeriesKey(Sample{Name: "count", Labels: map[string]string{"State": "AVAILABLE", "BundleId": "b-1"}})
+	b := SeriesKey(Sample{Name: "count", Labels: map[string]string{"BundleId": "b-1", "State": "AVAILABLE"}})
+	if a != b {
+		t.Fatalf("the key must not depend on the label order, got %q and %q", a, b)
+	}
+	if a == SeriesKey(Sample{Name: "count", Labels: map[string]string{"State": "AVAILABLE"}}) {
+		t.Fatal("series with different labels must have different keys")
+	}
+}
@@ -1,5 +1,5 @@
-import React, { ChangeEvent } from 'react';
-import { FieldSet, InlineField, Input, SecretInput } from '@grafana/ui';
+import React, { ChangeEvent, useState } from 'react';
+import { FieldSet, InlineField, Input, SecretInput, TextArea } from '@grafana/ui';
 import { DataSourcePluginOptionsEditorProps } from '@grafana/data';
 import { SammAwsDataSourceOptions, SammAwsSecureJsonData } from '../types';
 
@@ -16,6 +16,34 @@ export function ConfigEditor(props: Props) {
   jsonData.maxThrottleDelay = jsonData.maxThrottleDelay ?? 30000;
   jsonData.cacheSeconds = jsonData.cacheSeconds ?? 3600;
 
+  // Samplers are edited as JSON, the text is kept until it parses
+  const [samplersText, setSamplersText] = useState(JSON.stringify(jsonData.samplers ?? [], null, 2));
+  const [samplersError, setSamplersError] = useState('');
+
+  const onSamplersChange = (event: ChangeEvent<HTMLTextAreaElement>) => {
+    setSamplersText(event.target.value);
+    try {
+      const samplers = event.target.value.trim() === '' ? [] : JSON.parse(event.target.value);
+      if (!Array.isArray(samplers)) {
+        setSamplersError('Samplers must be a JSON array');
+        return;
+      }
+      setSamplersError('');
+      onOptionsChange({
+        ...options,
+        jsonData: {
+          ...jsonData,
+          samplers: samplers,
+        },
+      });
+    } catch {
+      setSamplersError('Invalid JSON');
+    }
+  };
+
+  // Empty optional numbers are unset, the plugin uses its defaults
+  const optionalNumber = (value: string) => (value === '' ? undefined : Number(value));
+
   const onRegionChange = (event: ChangeEvent<HTMLInputElement>) => {
     onOptionsChange({
       ...options,
@@ -232,6 +260,127 @@ export function ConfigEditor(props: Props) {
           </InlineField>
         </FieldSet>
       </div>
+      <div className='gf-form-group'>
+        <h3 className='page-heading'>Data settings</h3>
+        <FieldSet>
+          <InlineField label="Data Path" labelWidth={25} interactive tooltip={'Directory of the samples and of the inventory, by default plugins-data/sammaws under the Grafana data path'}>
+            <Input
+              id="config-editor-data-path"
+              onChange={(event: ChangeEvent<HTMLInputElement>) => {
+                onOptionsChange({
+                ...options,
+                jsonData: {
+                  ...jsonData,
+                  dataPath: event.target.value,
+                },
+                });}}
+              value={jsonData.dataPath}
+              placeholder="GF_PATHS_DATA/plugins-data/sammaws"
+              width={40}
+            />
+          </InlineField>
+          <InlineField label="Sample Retention (days)" labelWidth={25} interactive tooltip={'Days the samples are kept'}>
+            <Input
+              type="number"
+              id="config-editor-sample-retention-days"
+              onChange={(event: ChangeEvent<HTMLInputElement>) => {
+                onOptionsChange({
+                ...options,
+                jsonData: {
+                  ...jsonData,
+                  sampleRetentionDays: optionalNumber(event.target.value),
+                },
+                });}}
+              value={jsonData.sampleRetentionDays ?? ''}
+              placeholder="30"
+              width={15}
+            />
+          </InlineField>
+          <InlineField label="Samplers" labelWidth={25} interactive invalid={samplersError !== ''} error={samplersError}
+            tooltip={'JSON array of samplers: name, intervalSeconds and query, the query having the JSON model of a panel query'}>
+            <TextArea
+              id="config-editor-samplers"
+              onChange={onSamplersChange}
+              value={samplersText}
+              placeholder={'[{"name": "workspaces", "intervalSeconds": 300, "query": {"service": "workspaces", "service_query": "DescribeWorkspaces"}}]'}
+              rows={8}
+              cols={80}
+            />
+          </InlineField>
+          <InlineField label="Stream Interval (s)" labelWidth={25} interactive tooltip={'Seconds between the polls of the live streams, at least 10'}>
+            <Input
+              type="number"
+              id="config-editor-stream-interval-seconds"
+              onChange={(event: ChangeEvent<HTMLInputElement>) => {
+                onOptionsChange({
+                ...options,
+                jsonData: {
+                  ...jsonData,
+                  streamIntervalSeconds: optionalNumber(event.target.value),
+                },
+                });}}
+              value={jsonData.streamIntervalSeconds ?? ''}
+              placeholder="30"
+              width={15}
+            />
+          </InlineField>
+          <InlineField label="Inventory Interval (s)" labelWidth={25} interactive tooltip={'Seconds between the inventory snapshots, at least 300, empty or 0 disables the inventory'}>
+            <Input
+              type="number"
+              id="config-editor-inventory-interval-seconds"
+              onChange={(event: ChangeEvent<HTMLInputElement>) => {
+                onOptionsChange({
+                ...options,
+                jsonData: {
+                  ...jsonData,
+                  inventoryIntervalSeconds: optionalNumber(event.target.value),
+                },
+                });}}
+              value={jsonData.inventoryIntervalSeconds ?? ''}
+              placeholder="0"
+              width={15}
+            />
+          </InlineField>
+        </FieldSet>
+      </div>
+      <div className='gf-form-group'>
+        <h3 className='page-heading'>Pricing</h3>
+        <FieldSet>
+          <InlineField label="Pricing File" labelWidth={25} interactive tooltip={'CSV or JSON file of the WorkSpaces prices, the cost fields are added when it is set'}>
+            <Input
+              id="config-editor-pricing-file"
+              onChange={(event: ChangeEvent<HTMLInputElement>) => {
+                onOptionsChange({
+                ...options,
+                jsonData: {
+                  ...jsonData,
+                  pricingFile: event.target.value,
+                },
+                });}}
+              value={jsonData.pricingFile}
+              placeholder="/etc/grafana/workspaces-prices.csv"
+              width={40}
+            />
+          </InlineField>
+          <InlineField label="AutoStop Hours / Month" labelWidth={25} interactive tooltip={'Hours of use per month assumed for the cost of the AutoStop workspaces'}>
+            <Input
+              type="number"
+              id="config-editor-auto-stop-hours-per-month"
+              onChange={(event: ChangeEvent<HTMLInputElement>) => {
+                onOptionsChange({
+                ...options,
+                jsonData: {
+                  ...jsonData,
+                  autoStopHoursPerMonth: optionalNumber(event.target.value),
+                },
+                });}}
+              value={jsonData.autoStopHoursPerMonth ?? ''}
+              placeholder="0"
+              width={15}
+            />
+          </InlineField>
+        </FieldSet>
+      </div>
     </>
   );
 }
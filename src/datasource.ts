@@ -1,4 +1,4 @@
-import { Observable, of, from } from 'rxjs';
+import { Observable, of, from, merge } from 'rxjs';
 import { map } from 'rxjs/operators';
 import { 
     DataQueryRequest, 
@@ -6,9 +6,11 @@ import {
     DataSourceInstanceSettings, 
     ScopedVars,
     CustomVariableSupport,
+    LiveChannelScope,
 } from '@grafana/data';
 import { 
     DataSourceWithBackend, 
+    getGrafanaLiveSrv,
     getTemplateSrv,
     TemplateSrv, 
 } from '@grafana/runtime';
@@ -61,6 +63,8 @@ export class SammAwsDataSource extends DataSourceWithBackend<SammAwsQuery, SammA
                 ) {
         super(instanceSettings);
         this.variables = new SammAwsVariableSupport(this, this.templateSrv);
+        // CloudTrail lifecycle events are returned as annotation frames (time, title, text, tags)
+        this.annotations = {};
     }
 
     applyTemplateVariables(query: SammAwsQuery, scopedVars: ScopedVars) {
@@ -78,6 +82,28 @@ export class SammAwsDataSource extends DataSourceWithBackend<SammAwsQuery, SammA
         return query;
     }
 
+    // Queries of the live service subscribe to a channel (workspaces/state or
+    // appstream/sessions) and receive the changed rows pushed by the backend.
+    query(request: DataQueryRequest<SammAwsQuery>): Observable<DataQueryResponse> {
+        const live = request.targets.filter((target) => target.service === 'live' && !target.hide);
+        if (live.length === 0) {
+            return super.query(request);
+        }
+        const streams = live.map((target) => getGrafanaLiveSrv().getDataStream({
+            key: `${request.requestId}-${target.refId}`,
+            addr: {
+                scope: LiveChannelScope.DataSource,
+                namespace: this.uid,
+                path: target.service_query as string,
+            },
+        }));
+        const others = request.targets.filter((target) => target.service !== 'live');
+        if (others.length > 0) {
+            streams.push(super.query({...request, targets: others}));
+        }
+        return merge(...streams);
+    }
+
     async metricFindQuery(query: SammAwsQuery, options?: any): Promise<MetricFindValue[]> {
         const newquery = this.applyTemplateVariables(query, {});
         return this.postResource('query', newquery).then((result) => {
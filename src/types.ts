@@ -2,15 +2,30 @@ import { DataSourceJsonData } from '@grafana/data';
 import { DataQuery } from '@grafana/schema';
 import type {CascaderOption} from '@grafana/ui';
 
-export type SammAwsService = 'workspaces' | 'appstream' | 'ec2';
-export type SammAwsServiceQuery = (SammAwsWorkspacesServiceQuery | SammAwsAppstreamServiceQuery);
-export type SammAwsWorkspacesServiceQuery = 'DescribeWorkspaces' | 'DescribeWorkspacesConnectionStatus' | 'DescribeWorkspaceDirectories' | 'DescribeWorkspaceBundles';
+export type SammAwsService = 'workspaces' | 'appstream' | 'ec2' | 'samples' | 'cloudwatch' | 'cloudtrail' | 'audit' | 'live' | 'changes';
+export type SammAwsServiceQuery = (SammAwsWorkspacesServiceQuery | SammAwsAppstreamServiceQuery | SammAwsCloudwatchServiceQuery | SammAwsCloudtrailServiceQuery | SammAwsLiveServiceQuery);
+export type SammAwsCloudtrailServiceQuery = 'LookupEvents' | 'LookupEventsAnnotations' | 'LookupEventsLogs' | 'ActionAudit';
+export type SammAwsLiveServiceQuery = 'workspaces/state' | 'appstream/sessions';
+export type SammAwsCloudwatchServiceQuery = 'WorkSpacesMetrics' | 'AppStreamMetrics';
+export type SammAwsWorkspacesServiceQuery = 'DescribeWorkspaces' | 'DescribeWorkspaces360' | 'WorkspacesUsageReport' | 'DescribeWorkspacesConnectionStatus' | 'DescribeWorkspaceDirectories' | 'DescribeWorkspaceBundles' | 'DescribeIpGroups' |
+    'DescribeWorkspaceSnapshots' | 'DescribeWorkspaceImages' | 'DescribeWorkspaceImagePermissions' |
+    'DescribeWorkspacesPools' | 'DescribeWorkspacesPoolSessions' |
+    'DescribeAccount' | 'DescribeAccountModifications' | 'DescribeConnectionAliases' | 'DescribeConnectionAliasPermissions';
 export type SammAwsAppstreamServiceQuery = 'DescribeStacks' |
     'DescribeFleets' | 
     'DescribeSessions' |
     'DescribeDirectoryConfigs' |
     'ListAssociatedStacks' |
-    'ListAssociatedFleets';
+    'ListAssociatedFleets' |
+    'DescribeUsers' |
+    'DescribeUserStackAssociations' |
+    'DescribeImages' |
+    'DescribeImageBuilders' |
+    'DescribeApplications' |
+    'DescribeAppBlocks' |
+    'DescribeApplicationFleetAssociations' |
+    'DescribeEntitlements' |
+    'ListEntitledApplications';
 
 export type SammAwsProps = (SammAwsWorkspacesProps | SammAwsAppstreamProps | SammAwsEc2Props);
 export type SammAwsNoneProps = 'None';
@@ -50,6 +65,19 @@ export type SammAwsBaseQuery = (
     Limit?: number;
     filterConditions: Array<FilterCondition>;
     fieldList: Array<string | undefined>;
+    sortBy?: Array<SortKey>;
+    offset?: number;
+    rowLimit?: number;
+    groupBy?: Array<string>;
+    aggregations?: Array<Aggregation>;
+    format?: 'table' | 'numeric';
+    metricName?: string;
+    statistic?: string;
+    dimension?: 'WorkspaceId' | 'DirectoryId' | 'Fleet';
+    search?: string;
+    nextToken?: string;
+    idleDays?: number;
+    rarelyUsedDays?: number;
   }
 );
 
@@ -110,6 +138,20 @@ export interface SammAwsDataSourceOptions extends DataSourceJsonData {
   maxRetryDelay: number;
   maxThrottleDelay: number;
   cacheSeconds: number;
+  dataPath?: string;
+  sampleRetentionDays?: number;
+  samplers?: SamplerSettings[];
+  streamIntervalSeconds?: number;
+  /** seconds between the inventory snapshots, unset or 0 disables them */
+  inventoryIntervalSeconds?: number;
+  pricingFile?: string;
+  autoStopHoursPerMonth?: number;
+}
+
+export interface SamplerSettings {
+  name: string;
+  intervalSeconds: number;
+  query: Partial<SammAwsQuery>;
 }
 
 /**
@@ -120,10 +162,22 @@ export interface SammAwsSecureJsonData {
   accessToken?: string;
 }
 
+export interface Aggregation {
+  function: 'count' | 'count_distinct' | 'min' | 'max' | 'sum' | 'avg';
+  property?: string;
+  alias?: string;
+}
+
+export interface SortKey {
+  property: string;
+  desc?: boolean;
+}
+
 export interface FilterCondition {
   property: string;
   value: string;
   outProperty: string;
+  operator?: string;
 }
 
 export type FilterQueryDefinition = ({